@@ -0,0 +1,104 @@
+package gophertext
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// acrosticLineLen caps acrostic lines when MaxSentenceLen is unset
+const acrosticLineLen = 25
+
+// GenerateAcrostic generates one sentence per letter of word, each starting
+// with that letter, one per line. Non-letters in word are skipped.
+func (m *MarkovModel) GenerateAcrostic(word string) (string, error) {
+	var initials []rune
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			initials = append(initials, r)
+		}
+	}
+	return m.GenerateWithInitials(initials)
+}
+
+// GenerateWithInitials generates one sentence per rune, each starting with
+// that letter, one per line. Lines start from a sentence-initial prefix
+// when the model has one for the letter, and end at the first sentence
+// terminator or after MaxSentenceLen words.
+func (m *MarkovModel) GenerateWithInitials(initials []rune) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.chain) == 0 {
+		return "", fmt.Errorf("model not trained")
+	}
+	maxLen := m.config.MaxSentenceLen
+	if maxLen <= 0 {
+		maxLen = acrosticLineLen
+	}
+
+	o := newGenerateOptions(nil)
+	lines := make([]string, 0, len(initials))
+	for _, initial := range initials {
+		candidates := m.prefixesWithInitial(initial)
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("no prefix starts with %q", initial)
+		}
+
+		tokens := splitKey(candidates[m.rng.Intn(len(candidates))])
+		for len(tokens) < maxLen && !m.splitter.IsTerminal(tokens[len(tokens)-1]) {
+			key := joinKey(tokens[len(tokens)-m.config.Order:])
+			next, ok := m.sample(key, m.chain[key], o)
+			if !ok {
+				break
+			}
+			tokens = append(tokens, next)
+		}
+		lines = append(lines, capitalizeFirst(m.formatTokens(tokens, o)))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// prefixesWithInitial returns the sentence-initial prefixes whose first
+// letter is initial, or any such prefix if no sentence start matches
+func (m *MarkovModel) prefixesWithInitial(initial rune) []string {
+	initial = unicode.ToLower(initial)
+	var matches []string
+	for _, prefix := range m.prefixIndex().starts {
+		if firstLetter(prefix) == initial && !hasUnknown(prefix) {
+			matches = append(matches, prefix)
+		}
+	}
+	if len(matches) > 0 {
+		return matches
+	}
+	for _, prefix := range m.prefixIndex().prefixes {
+		if firstLetter(prefix) == initial && !hasUnknown(prefix) {
+			matches = append(matches, prefix)
+		}
+	}
+	return matches
+}
+
+// firstLetter returns the lowercased first letter of s, skipping leading
+// quotes and punctuation
+func firstLetter(s string) rune {
+	s = strings.TrimLeftFunc(s, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	r, _ := utf8.DecodeRuneInString(s)
+	if !unicode.IsLetter(r) {
+		return 0
+	}
+	return unicode.ToLower(r)
+}
+
+// capitalizeFirst uppercases the first letter of s
+func capitalizeFirst(s string) string {
+	i := strings.IndexFunc(s, unicode.IsLetter)
+	if i < 0 {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s[i:])
+	return s[:i] + string(unicode.ToUpper(r)) + s[i+size:]
+}
@@ -0,0 +1,109 @@
+package gophertext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// determiners can't follow an article or repeat, so a second one in a row is
+// a generation artifact. "that" is left out since "that that" is valid.
+var determiners = map[string]bool{
+	"a": true, "an": true, "the": true, "this": true,
+	"these": true, "those": true, "my": true, "your": true, "his": true,
+	"her": true, "its": true, "our": true, "their": true,
+}
+
+// Words whose spelling and sound disagree about a vowel start
+var (
+	consonantSoundPrefixes = []string{"eu", "ewe", "one", "once", "uni", "use", "usu", "uti", "ura", "ure", "uro"}
+	vowelSoundPrefixes     = []string{"heir", "honest", "honor", "honour", "hour"}
+)
+
+// fixAgreement repairs the most visible English agreement errors in
+// generated words: doubled determiners ("the the"), the wrong indefinite
+// article ("a apple") and a lowercase word after a quoted sentence end.
+func fixAgreement(words []string) []string {
+	fixed := words[:0]
+	for _, w := range words {
+		if n := len(fixed); n > 0 && determiners[strings.ToLower(w)] {
+			prev := strings.ToLower(fixed[n-1])
+			if prev == "a" || prev == "an" || prev == "the" || prev == strings.ToLower(w) {
+				continue
+			}
+		}
+		fixed = append(fixed, w)
+	}
+
+	for i := 0; i+1 < len(fixed); i++ {
+		if article := strings.ToLower(fixed[i]); article == "a" || article == "an" {
+			want := "a"
+			if vowelSound(coreWord(fixed[i+1])) {
+				want = "an"
+			}
+			if want != article {
+				if fixed[i][0] == 'A' {
+					want = capitalizeFirst(want)
+				}
+				fixed[i] = want
+			}
+		}
+	}
+
+	for i := 1; i < len(fixed); i++ {
+		if endsQuotedSentence(fixed[i-1]) || (startsWithQuote(fixed[i]) && endsSentence(fixed[i-1])) {
+			fixed[i] = capitalizeFirst(fixed[i])
+		}
+	}
+	return fixed
+}
+
+// vowelSound guesses whether word is pronounced with a leading vowel
+func vowelSound(word string) bool {
+	lower := strings.ToLower(word)
+	for _, p := range vowelSoundPrefixes {
+		if strings.HasPrefix(lower, p) {
+			return true
+		}
+	}
+	for _, p := range consonantSoundPrefixes {
+		if strings.HasPrefix(lower, p) {
+			return false
+		}
+	}
+	r, _ := utf8.DecodeRuneInString(lower)
+	return strings.ContainsRune("aeiou8", r) || lower == "11" || lower == "18"
+}
+
+// endsSentence reports whether word ends in terminal punctuation, ignoring
+// closing quotes and brackets after it
+func endsSentence(word string) bool {
+	trimmed := strings.TrimRightFunc(word, func(r rune) bool { return isQuote(r) || r == ')' })
+	r, _ := utf8.DecodeLastRuneInString(trimmed)
+	return r == '.' || r == '!' || r == '?'
+}
+
+// endsQuotedSentence reports whether word closes a quotation that ended a
+// sentence, as in `stop."`
+func endsQuotedSentence(word string) bool {
+	r, _ := utf8.DecodeLastRuneInString(word)
+	return isQuote(r) && endsSentence(word)
+}
+
+func startsWithQuote(word string) bool {
+	r, size := utf8.DecodeRuneInString(word)
+	if !isQuote(r) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(word[size:])
+	return unicode.IsLetter(next)
+}
+
+// isEnglish reports whether tag is English or untagged
+func isEnglish(tag string) bool {
+	if tag == "" {
+		return true
+	}
+	_, base, err := parseLanguage(tag)
+	return err == nil && base == "en"
+}
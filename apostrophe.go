@@ -0,0 +1,69 @@
+package gophertext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// clitics are the contraction endings some corpora split from their word,
+// as in Penn Treebank style "do n't" or "it 's"
+var clitics = map[string]bool{
+	"n't": true, "'s": true, "'re": true, "'ve": true,
+	"'ll": true, "'d": true, "'m": true, "'t": true,
+}
+
+// normalizeApostrophes writes apostrophes as ' so "don’t" and "don't" are
+// the same token. Only a curly mark between two letters, or opening a word
+// as in "’tis", is an apostrophe; elsewhere it is a quotation mark and kept.
+func normalizeApostrophes(text string) string {
+	if !strings.ContainsAny(text, "’ʼ‘") {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	prev := rune(0)
+	for i, r := range text {
+		switch r {
+		case '’', 'ʼ', '‘':
+			next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+			elision := r != '‘' && (prev == 0 || unicode.IsSpace(prev))
+			if unicode.IsLetter(next) && (unicode.IsLetter(prev) || elision) {
+				r = '\''
+			}
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// joinContractions reattaches contraction endings split off by the corpus:
+// "do n't" becomes "don't", "it 's" becomes "it's" and "don ' t" becomes
+// "don't". Endings are only joined to a word ending in a letter.
+func joinContractions(words []string) []string {
+	joined := words[:0]
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if len(joined) > 0 && endsInLetter(joined[len(joined)-1]) {
+			last := &joined[len(joined)-1]
+			switch {
+			case clitics[strings.ToLower(w)]:
+				*last += w
+				continue
+			case w == "'" && i+1 < len(words) && clitics["'"+strings.ToLower(words[i+1])]:
+				*last += w + words[i+1]
+				i++
+				continue
+			}
+		}
+		joined = append(joined, w)
+	}
+	return joined
+}
+
+// endsInLetter reports whether the last rune of w is a letter
+func endsInLetter(w string) bool {
+	r, _ := utf8.DecodeLastRuneInString(w)
+	return unicode.IsLetter(r)
+}
@@ -0,0 +1,190 @@
+package gophertext
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+// ApproxConfig sizes the structures of an ApproxModel
+type ApproxConfig struct {
+	Buckets     int // Prefix buckets (default 65536)
+	Candidates  int // Candidate suffixes kept per bucket (default 32)
+	SketchWidth int // Counters per sketch row (default 1<<20)
+	SketchDepth int // Sketch rows (default 4)
+	Restarts    int // Prefixes sampled for random restarts (default 4096)
+}
+
+// ApproxModel trades exactness for memory on corpora whose exact chain
+// doesn't fit. Prefixes hash into a fixed number of buckets, each holding a
+// short candidate list of suffixes, and transition counts live in a
+// count-min sketch keyed by the full (prefix, suffix) pair. Memory use is
+// fixed by ApproxConfig regardless of corpus size.
+type ApproxModel struct {
+	text   *MarkovModel // Tokenization and post-processing settings
+	approx ApproxConfig
+
+	mu       sync.RWMutex
+	buckets  [][]string
+	sketch   *countMinSketch
+	restarts []string
+	seen     int // Prefixes offered to the restart reservoir
+}
+
+// NewApproxModel creates an approximate model
+func NewApproxModel(cfg MarkovConfig, approx ApproxConfig) *ApproxModel {
+	if approx.Buckets <= 0 {
+		approx.Buckets = 1 << 16
+	}
+	if approx.Candidates <= 0 {
+		approx.Candidates = 32
+	}
+	if approx.SketchWidth <= 0 {
+		approx.SketchWidth = 1 << 20
+	}
+	if approx.SketchDepth <= 0 {
+		approx.SketchDepth = 4
+	}
+	if approx.Restarts <= 0 {
+		approx.Restarts = 4096
+	}
+
+	// Training streams the corpus, so OrderAuto can't see it up front
+	text := NewMarkovModel(cfg)
+	if text.config.Order == OrderAuto {
+		text.config.Order = defaultOrder
+	}
+	return &ApproxModel{
+		text:    text,
+		approx:  approx,
+		buckets: make([][]string, approx.Buckets),
+		sketch:  newCountMinSketch(approx.SketchWidth, approx.SketchDepth),
+	}
+}
+
+func (a *ApproxModel) bucket(prefix string) int {
+	h := fnv.New32a()
+	h.Write([]byte(prefix))
+	return int(h.Sum32() % uint32(len(a.buckets)))
+}
+
+// BuildModel streams text into the sketch and candidate lists
+func (a *ApproxModel) BuildModel(text string) {
+	words := a.text.trainingTokens(text)
+	order := a.text.config.Order
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := 0; i+order < len(words); i++ {
+		prefix := joinKey(words[i : i+order])
+		suffix := words[i+order]
+		a.sketch.add(prefix, suffix, 1)
+		a.offerCandidate(prefix, suffix)
+		a.offerRestart(prefix)
+	}
+}
+
+// offerCandidate keeps the bucket's candidate list filled with the suffixes
+// the sketch considers most frequent for their prefixes
+func (a *ApproxModel) offerCandidate(prefix, suffix string) {
+	b := a.bucket(prefix)
+	list := a.buckets[b]
+	for _, c := range list {
+		if c == suffix {
+			return
+		}
+	}
+	if len(list) < a.approx.Candidates {
+		a.buckets[b] = append(list, strings.Clone(suffix))
+		return
+	}
+
+	weakest, weakestCount := -1, a.sketch.estimate(prefix, suffix)
+	for i, c := range list {
+		if n := a.sketch.estimate(prefix, c); n < weakestCount {
+			weakest, weakestCount = i, n
+		}
+	}
+	if weakest >= 0 {
+		list[weakest] = strings.Clone(suffix)
+	}
+}
+
+// offerRestart maintains a uniform reservoir sample of prefixes
+func (a *ApproxModel) offerRestart(prefix string) {
+	a.seen++
+	if len(a.restarts) < a.approx.Restarts {
+		a.restarts = append(a.restarts, strings.Clone(prefix))
+	} else if j := a.text.rng.Intn(a.seen); j < len(a.restarts) {
+		a.restarts[j] = strings.Clone(prefix)
+	}
+}
+
+// Generate outputs words sampled from the approximate transition counts.
+// Word counts are limited as for MarkovModel.Generate, and like it, counts
+// between 1 and Order give Order words.
+func (a *ApproxModel) Generate(wordCount int) (string, error) {
+	a.text.mu.RLock()
+	err := a.text.checkWordCount(wordCount)
+	a.text.mu.RUnlock()
+	if err != nil || wordCount == 0 {
+		return "", err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if len(a.restarts) == 0 {
+		return "", fmt.Errorf("model not trained")
+	}
+
+	words := splitKey(a.restarts[a.text.rng.Intn(len(a.restarts))])
+	prefix := append([]string(nil), words...)
+	candidates := make([]string, 0, a.approx.Candidates)
+	weights := make([]uint64, 0, a.approx.Candidates)
+
+	deadEnds := 0
+	for len(words) < wordCount {
+		key := joinKey(prefix)
+		candidates, weights = candidates[:0], weights[:0]
+		var total uint64
+		for _, c := range a.buckets[a.bucket(key)] {
+			if n := a.sketch.estimate(key, c); n > 0 {
+				candidates = append(candidates, c)
+				weights = append(weights, uint64(n))
+				total += uint64(n)
+			}
+		}
+
+		if total == 0 {
+			// Dead end: restart from a sampled prefix, unless the restart
+			// prefixes keep leading nowhere because their candidates were evicted
+			if deadEnds++; deadEnds == 8 {
+				return "", fmt.Errorf("broken chain")
+			}
+			prefix = splitKey(a.restarts[a.text.rng.Intn(len(a.restarts))])
+			continue
+		}
+		deadEnds = 0
+
+		r := uint64(a.text.rng.Int63n(int64(total)))
+		next := candidates[len(candidates)-1]
+		for i, w := range weights {
+			if r < w {
+				next = candidates[i]
+				break
+			}
+			r -= w
+		}
+
+		display := next
+		if a.text.splitter.IsTerminal(words[len(words)-1]) {
+			display = capitalizeFirst(next)
+		}
+		words = append(words, display)
+		prefix = append(prefix[1:], next)
+	}
+
+	return a.text.postProcessText(strings.Join(words, " "), newGenerateOptions(nil)), nil
+}
@@ -0,0 +1,14 @@
+package gophertext
+
+import "testing"
+
+func TestApproxOrderAuto(t *testing.T) {
+	a := NewApproxModel(MarkovConfig{Order: OrderAuto}, ApproxConfig{Buckets: 1 << 12, SketchWidth: 1 << 14})
+	if a.text.config.Order != defaultOrder {
+		t.Fatalf("order = %d, want %d", a.text.config.Order, defaultOrder)
+	}
+	a.BuildModel(testCorpus(t))
+	if _, err := a.Generate(20); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,141 @@
+package gophertext
+
+import (
+	"sort"
+	"strings"
+)
+
+// auditProbability is the transition probability above which flagged
+// suffixes are reported as likely to appear in generated text
+const auditProbability = 0.1
+
+// AuditReport summarizes flagged content found in a trained model
+type AuditReport struct {
+	Vocabulary  int                 // Distinct words scanned
+	Flagged     []AuditFinding      // Vocabulary words matching the blocklist
+	Transitions []FlaggedTransition // High-probability transitions into flagged words
+	PII         []PIIFinding        // Words that look like personal data
+}
+
+// AuditFinding is a vocabulary word matching a blocklist term
+type AuditFinding struct {
+	Word        string
+	Term        string
+	Occurrences int
+}
+
+// FlaggedTransition is a likely transition that emits a flagged word
+type FlaggedTransition struct {
+	Prefix      string
+	Suffix      string
+	Term        string
+	Probability float64
+}
+
+// PIIFinding is a vocabulary word that looks like personal data
+type PIIFinding struct {
+	Word string
+	Kind string // "email", "phone", "ssn" or "card"
+}
+
+// Clean reports whether the audit found nothing
+func (r AuditReport) Clean() bool {
+	return len(r.Flagged) == 0 && len(r.PII) == 0
+}
+
+// AuditModel scans the vocabulary and transitions for blocklisted terms and
+// PII-looking strings. Terms match whole words case-insensitively; a
+// trailing "*" matches any word starting with the term.
+func (m *MarkovModel) AuditModel(blocklist []string) AuditReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for prefix, suffixes := range m.chain {
+		for _, w := range splitKey(prefix) {
+			if _, ok := counts[w]; !ok {
+				counts[w] = 0
+			}
+		}
+		for s, n := range suffixes {
+			counts[s] += n
+		}
+	}
+
+	report := AuditReport{Vocabulary: len(counts)}
+	flagged := make(map[string]string)
+	for word, n := range counts {
+		if term := matchBlocklist(word, blocklist); term != "" {
+			flagged[word] = term
+			report.Flagged = append(report.Flagged, AuditFinding{Word: word, Term: term, Occurrences: n})
+		}
+		if kind := piiKind(word); kind != "" {
+			report.PII = append(report.PII, PIIFinding{Word: word, Kind: kind})
+		}
+	}
+
+	if len(flagged) > 0 {
+		for prefix, suffixes := range m.chain {
+			total := suffixTotal(suffixes)
+			for suffix, n := range suffixes {
+				term, ok := flagged[suffix]
+				if !ok {
+					continue
+				}
+				p := float64(n) / float64(total)
+				if p >= auditProbability {
+					report.Transitions = append(report.Transitions, FlaggedTransition{
+						Prefix: prefix, Suffix: suffix, Term: term, Probability: p,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(report.Flagged, func(i, j int) bool {
+		return report.Flagged[i].Occurrences > report.Flagged[j].Occurrences ||
+			(report.Flagged[i].Occurrences == report.Flagged[j].Occurrences && report.Flagged[i].Word < report.Flagged[j].Word)
+	})
+	sort.Slice(report.Transitions, func(i, j int) bool {
+		return report.Transitions[i].Probability > report.Transitions[j].Probability ||
+			(report.Transitions[i].Probability == report.Transitions[j].Probability && report.Transitions[i].Prefix < report.Transitions[j].Prefix)
+	})
+	sort.Slice(report.PII, func(i, j int) bool { return report.PII[i].Word < report.PII[j].Word })
+	return report
+}
+
+func matchBlocklist(word string, blocklist []string) string {
+	core := strings.ToLower(coreWord(word))
+	if core == "" {
+		return ""
+	}
+	for _, term := range blocklist {
+		t := strings.ToLower(strings.TrimSpace(term))
+		if stem, ok := strings.CutSuffix(t, "*"); ok {
+			if stem != "" && strings.HasPrefix(core, stem) {
+				return term
+			}
+		} else if core == t {
+			return term
+		}
+	}
+	return ""
+}
+
+// countSuffixes tallies how often each suffix occurs in a suffix list
+func countSuffixes(suffixes []string) map[string]int {
+	counts := make(map[string]int, len(suffixes))
+	for _, s := range suffixes {
+		counts[s]++
+	}
+	return counts
+}
+
+// suffixTotal returns the number of suffix occurrences in counts
+func suffixTotal(counts map[string]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
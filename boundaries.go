@@ -0,0 +1,98 @@
+package gophertext
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultForbiddenEnders lists words that leave a sentence hanging when it
+// stops on them
+var DefaultForbiddenEnders = []string{
+	"a", "an", "and", "but", "of", "or", "the", "to", "with",
+}
+
+// SetForbiddenBoundaries changes the words generated sentences may not
+// start or end with. Either list may be nil.
+func (m *MarkovModel) SetForbiddenBoundaries(starters, enders []string) error {
+	for _, w := range slices.Concat(starters, enders) {
+		if coreWord(w) == "" {
+			return fmt.Errorf("invalid sentence boundary word %q", w)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.ForbiddenStarters = slices.Clone(starters)
+	m.config.ForbiddenEnders = slices.Clone(enders)
+	return nil
+}
+
+// boundaryWords normalizes a list of sentence boundary words for lookup.
+// Callers hold the lock.
+func (m *MarkovModel) boundaryWords(words []string) map[string]bool {
+	if len(words) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[strings.ToLower(coreWord(m.normalizeText(w)))] = true
+	}
+	return set
+}
+
+// noteSentence records where in its sentence the next word goes: whether
+// it starts one and, for PronounConsistency, the person of the sentence so
+// far. sample reads both.
+func (m *MarkovModel) noteSentence(tokens []string, o *generateOptions) {
+	o.atStart = len(tokens) == 0 || m.splitter.IsTerminal(tokens[len(tokens)-1])
+	if m.config.PronounConsistency > 0 && isEnglish(m.config.Language) {
+		o.person = m.sentencePerson(tokens)
+	}
+}
+
+// dropBoundaryWords removes the candidates after prefix that would start a
+// sentence with a forbidden starter or end one on a forbidden ender, or
+// end a sentence that can only go on with a forbidden starter. If every
+// candidate is forbidden they are all kept, so the rule never causes a
+// dead end.
+func (m *MarkovModel) dropBoundaryWords(prefix string, candidates []Candidate, o *generateOptions) []Candidate {
+	if o.starters == nil && o.enders == nil {
+		return candidates
+	}
+	kept := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		core := strings.ToLower(coreWord(c.Word))
+		if o.atStart && o.starters[core] {
+			continue
+		}
+		if m.splitter.IsTerminal(c.Word) && (o.enders[core] || !m.canStart(prefix, c.Word, o)) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	if len(kept) == 0 {
+		return candidates
+	}
+	return kept
+}
+
+// canStart reports whether the sentence ending with word after prefix can
+// go on with a word that isn't a forbidden starter. Prefixes the chain
+// doesn't continue are left to the fallback strategy and report true.
+func (m *MarkovModel) canStart(prefix, word string, o *generateOptions) bool {
+	if len(o.starters) == 0 {
+		return true
+	}
+	words := splitKey(prefix)
+	words = append(words[max(0, len(words)-m.config.Order+1):], word)
+	next := m.chain[joinKey(words)]
+	if len(next) == 0 {
+		return true
+	}
+	for s := range next {
+		if !o.starters[strings.ToLower(coreWord(s))] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package gophertext
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+
+	"github.com/jasonlovesdoggo/gophertext/textsim"
+)
+
+// CacheConfig controls an OutputCache
+type CacheConfig struct {
+	Size      int     // Recent outputs remembered per prompt and length (default 32)
+	Threshold float64 // Similarity at or above which an output is a near repeat (default 0.5)
+	Retries   int     // Extra attempts DistinctGenerate makes before settling (default 8)
+	MaxKeys   int     // Prompt, length and key combinations remembered, least recently used dropped first (default 1024)
+
+	Similarity textsim.Metric // Compares outputs (default textsim.Similarity, word bigram Jaccard)
+}
+
+// OutputCache remembers a model's recent generations so repeats can be
+// avoided, for example in UI placeholders where the same sentence showing
+// up twice looks broken. Outputs are grouped by prompt and word count, and
+// by the key given with WithCacheKey, as other generation options can't be
+// compared. Give calls with different options different keys.
+type OutputCache struct {
+	model *MarkovModel
+	cfg   CacheConfig
+
+	mu     sync.Mutex
+	recent map[string]*list.Element // Key -> element of lru holding a *cacheEntry
+	lru    *list.List               // Entries, most recently used first
+}
+
+// cacheEntry is the remembered outputs for one key, oldest first
+type cacheEntry struct {
+	key     string
+	outputs []string
+}
+
+// NewOutputCache creates an empty cache in front of m
+func NewOutputCache(m *MarkovModel, cfg CacheConfig) *OutputCache {
+	if cfg.Size <= 0 {
+		cfg.Size = 32
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 0.5
+	}
+	if cfg.Retries <= 0 {
+		cfg.Retries = 8
+	}
+	if cfg.MaxKeys <= 0 {
+		cfg.MaxKeys = 1024
+	}
+	if cfg.Similarity == nil {
+		cfg.Similarity = textsim.Similarity
+	}
+	return &OutputCache{
+		model:  m,
+		cfg:    cfg,
+		recent: make(map[string]*list.Element),
+		lru:    list.New(),
+	}
+}
+
+// WithCacheKey groups an OutputCache's outputs under key as well as the
+// prompt and word count, so calls made with different options don't count
+// as repeats of each other. Generation itself ignores it.
+func WithCacheKey(key string) GenerateOption {
+	return func(o *generateOptions) {
+		o.cacheKey = key
+	}
+}
+
+// Generate is MarkovModel.Generate, remembering the output
+func (c *OutputCache) Generate(wordCount int, opts ...GenerateOption) (string, error) {
+	return c.GenerateFrom("", wordCount, opts...)
+}
+
+// GenerateFrom is MarkovModel.GenerateFrom, remembering the output
+func (c *OutputCache) GenerateFrom(prompt string, wordCount int, opts ...GenerateOption) (string, error) {
+	text, err := c.generate(prompt, wordCount, opts)
+	if err != nil {
+		return "", err
+	}
+	c.remember(cacheKey(prompt, wordCount, opts), text)
+	return text, nil
+}
+
+// DistinctGenerate is Generate, retrying while the output is a near repeat
+// of a recent one. If every attempt is too similar, the least similar
+// output is returned.
+func (c *OutputCache) DistinctGenerate(wordCount int, opts ...GenerateOption) (string, error) {
+	return c.DistinctGenerateFrom("", wordCount, opts...)
+}
+
+// DistinctGenerateFrom is DistinctGenerate continuing prompt
+func (c *OutputCache) DistinctGenerateFrom(prompt string, wordCount int, opts ...GenerateOption) (string, error) {
+	key := cacheKey(prompt, wordCount, opts)
+	recent := c.outputs(key)
+
+	best, bestScore := "", 2.0
+	for attempt := 0; attempt <= c.cfg.Retries; attempt++ {
+		text, err := c.generate(prompt, wordCount, opts)
+		if err != nil {
+			return "", err
+		}
+		score := 0.0
+		for _, other := range recent {
+			score = max(score, c.cfg.Similarity(text, other))
+		}
+		if score < bestScore {
+			best, bestScore = text, score
+		}
+		if score < c.cfg.Threshold {
+			break
+		}
+	}
+	c.remember(key, best)
+	return best, nil
+}
+
+// Recent returns the remembered outputs for prompt and wordCount, and the
+// key given with WithCacheKey in opts, oldest first
+func (c *OutputCache) Recent(prompt string, wordCount int, opts ...GenerateOption) []string {
+	return c.outputs(cacheKey(prompt, wordCount, opts))
+}
+
+// Clear forgets every remembered output
+func (c *OutputCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recent = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+func (c *OutputCache) generate(prompt string, wordCount int, opts []GenerateOption) (string, error) {
+	if prompt == "" {
+		return c.model.Generate(wordCount, opts...)
+	}
+	return c.model.GenerateFrom(prompt, wordCount, opts...)
+}
+
+// outputs returns a copy of the outputs remembered under key
+func (c *OutputCache) outputs(key string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.recent[key]; ok {
+		return append([]string(nil), el.Value.(*cacheEntry).outputs...)
+	}
+	return nil
+}
+
+// remember adds text to the outputs of key, dropping the least recently
+// used key when there are more than MaxKeys
+func (c *OutputCache) remember(key, text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.recent[key]
+	if ok {
+		c.lru.MoveToFront(el)
+	} else {
+		el = c.lru.PushFront(&cacheEntry{key: key})
+		c.recent[key] = el
+		if c.lru.Len() > c.cfg.MaxKeys {
+			oldest := c.lru.Back()
+			c.lru.Remove(oldest)
+			delete(c.recent, oldest.Value.(*cacheEntry).key)
+		}
+	}
+	entry := el.Value.(*cacheEntry)
+	entry.outputs = append(entry.outputs, text)
+	if len(entry.outputs) > c.cfg.Size {
+		entry.outputs = entry.outputs[len(entry.outputs)-c.cfg.Size:]
+	}
+}
+
+func cacheKey(prompt string, wordCount int, opts []GenerateOption) string {
+	key := newGenerateOptions(opts).cacheKey
+	return strconv.Itoa(wordCount) + "\x00" + strconv.Itoa(len(key)) + "\x00" + key + prompt
+}
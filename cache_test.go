@@ -0,0 +1,42 @@
+package gophertext
+
+import "testing"
+
+func TestOutputCacheMaxKeys(t *testing.T) {
+	m := NewMarkovModel(MarkovConfig{Order: 1, Seed: 1})
+	if err := m.BuildModel(testCorpus(t)); err != nil {
+		t.Fatal(err)
+	}
+	c := NewOutputCache(m, CacheConfig{MaxKeys: 3})
+
+	for n := 1; n <= 3; n++ {
+		if _, err := c.Generate(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := c.Generate(4, WithCacheKey(string(rune('a'+i)))); err != nil {
+			t.Fatal(err)
+		}
+		if len(c.recent) > 3 || c.lru.Len() != len(c.recent) {
+			t.Fatalf("cache holds %d keys (%d in LRU order), want at most 3", len(c.recent), c.lru.Len())
+		}
+	}
+	if got := c.Recent("", 4, WithCacheKey("j")); len(got) != 1 {
+		t.Errorf("newest key remembers %d outputs, want 1", len(got))
+	}
+	if got := c.Recent("", 1); len(got) != 0 {
+		t.Errorf("evicted key still remembers %d outputs", len(got))
+	}
+
+	// Using length 1 again makes length 2 the least recently used
+	c = NewOutputCache(m, CacheConfig{MaxKeys: 2})
+	for _, n := range []int{1, 2, 1, 3} {
+		if _, err := c.Generate(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(c.Recent("", 1)) != 2 || len(c.Recent("", 2)) != 0 {
+		t.Error("least recently used key was not the one dropped")
+	}
+}
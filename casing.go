@@ -0,0 +1,145 @@
+package gophertext
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// caseCount tallies how a word was written away from sentence starts
+type caseCount struct {
+	Capitalized int // Occurrences starting with a capital letter
+	Upper       int // Occurrences in capitals throughout, such as "NASA"
+	Total       int // All occurrences
+}
+
+// casingCount is one entry of the casing table as encoded
+type casingCount struct {
+	Word                      string
+	Capitalized, Upper, Total int
+}
+
+// recordCasing counts how often each word of text is capitalized or
+// written in capitals where a capital isn't required: not at a sentence
+// start or the start of a quotation. Words are keyed by their normalized
+// form without surrounding punctuation.
+func (m *MarkovModel) recordCasing(text string) {
+	raw := joinContractions(strings.Fields(normalizeApostrophes(nfc(text))))
+	counts := make(map[string]caseCount)
+	start := true
+	m.mu.RLock()
+	for _, w := range raw {
+		first, _ := utf8.DecodeRuneInString(w)
+		if !start && unicode.IsLetter(first) {
+			key := coreWord(m.normalizeText(w))
+			c := counts[key]
+			if unicode.IsUpper(first) {
+				c.Capitalized++
+			}
+			if isAcronym(coreWord(w)) {
+				c.Upper++
+			}
+			c.Total++
+			counts[key] = c
+		}
+		start = m.splitter.IsTerminal(w)
+	}
+	m.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addCasing(counts)
+}
+
+// addCasing sums counts into the casing table. Callers hold the lock.
+func (m *MarkovModel) addCasing(counts map[string]caseCount) {
+	if m.casing == nil {
+		m.casing = make(map[string]caseCount, len(counts))
+	}
+	for w, c := range counts {
+		prev := m.casing[w]
+		m.casing[w] = caseCount{prev.Capitalized + c.Capitalized, prev.Upper + c.Upper, prev.Total + c.Total}
+	}
+}
+
+// isAcronym reports whether word is at least two capital letters, possibly
+// with digits, periods or ampersands, as in "NASA", "U.S" or "AT&T"
+func isAcronym(word string) bool {
+	letters := 0
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			letters++
+		case unicode.IsDigit(r) || r == '.' || r == '&':
+		default:
+			return false
+		}
+	}
+	return letters >= 2
+}
+
+// restoreCasing writes acronyms in capitals and capitalizes the words the
+// corpus capitalized at least RestoreCase of the time. Callers hold the lock.
+func (m *MarkovModel) restoreCasing(words []string) {
+	cfg := m.config
+	if cfg.RestoreCase <= 0 && !cfg.DetectAcronyms && len(cfg.Acronyms) == 0 {
+		return
+	}
+	for i, w := range words {
+		core := coreWord(w)
+		if core == "" {
+			continue
+		}
+		key := strings.ToLower(core)
+		c := m.casing[key]
+		switch {
+		case m.isKnownAcronym(key, c):
+			core = strings.ToUpper(core)
+		case cfg.RestoreCase > 0 && c.Total > 0 && float64(c.Capitalized) >= cfg.RestoreCase*float64(c.Total):
+			core = capitalizeFirst(core)
+		default:
+			continue
+		}
+		at := strings.Index(strings.ToLower(w), key)
+		words[i] = w[:at] + core + w[at+len(key):]
+	}
+}
+
+// isKnownAcronym reports whether the lowercased word is listed in Acronyms
+// or, with DetectAcronyms, was mostly written in capitals in the corpus
+func (m *MarkovModel) isKnownAcronym(key string, c caseCount) bool {
+	if m.config.DetectAcronyms && c.Upper*2 > c.Total {
+		return true
+	}
+	for _, a := range m.config.Acronyms {
+		if strings.EqualFold(a, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Acronyms returns the words written in capitals in generated text: those
+// listed in the config merged with those detected in the corpus, sorted
+func (m *MarkovModel) Acronyms() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	seen := make(map[string]bool)
+	for _, a := range m.config.Acronyms {
+		seen[strings.ToUpper(a)] = true
+	}
+	if m.config.DetectAcronyms {
+		for w, c := range m.casing {
+			if m.isKnownAcronym(w, c) {
+				seen[strings.ToUpper(w)] = true
+			}
+		}
+	}
+	acronyms := make([]string, 0, len(seen))
+	for a := range seen {
+		acronyms = append(acronyms, a)
+	}
+	sort.Strings(acronyms)
+	return acronyms
+}
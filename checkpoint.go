@@ -0,0 +1,134 @@
+package gophertext
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// checkpointFile is the name of the checkpoint written inside the checkpoint directory
+const checkpointFile = "checkpoint.gtc"
+
+// TrainOption customizes a single call to BuildModel
+type TrainOption func(*trainOptions)
+
+type trainOptions struct {
+	checkpointDir   string
+	checkpointEvery time.Duration
+	cooccurrence    *CooccurrenceMatrix // Filled from the token stream when set
+	gate            *QualityGate        // Drops low-quality sentences before tokenizing
+	budget          int64               // Projected bytes training may use (0 = unchecked)
+	warnBudget      func(SizeEstimate)  // Called instead of failing when over budget
+	dryRun          *DryRunReport       // Count into this report instead of training
+	orderHeuristic  OrderHeuristic      // Resolves OrderAuto (nil = RecommendOrder)
+}
+
+func newTrainOptions(opts []TrainOption) *trainOptions {
+	o := &trainOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithCheckpoint periodically writes training progress to dir, at most once
+// per every. A crashed run can continue with ResumeTraining followed by
+// BuildModel on the same corpus with the same option.
+func WithCheckpoint(dir string, every time.Duration) TrainOption {
+	return func(o *trainOptions) {
+		o.checkpointDir = dir
+		o.checkpointEvery = every
+	}
+}
+
+type checkpoint struct {
+	Model  []byte // Saved model covering every transition before Offset
+	Offset int    // Prefix start index training resumes from
+	Corpus uint64 // Digest of the token stream being trained
+}
+
+// ResumeTraining loads the checkpoint in dir. Calling BuildModel on the
+// returned model with the original corpus skips the part already trained.
+func ResumeTraining(dir string) (*MarkovModel, error) {
+	data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp checkpoint
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cp); err != nil {
+		return nil, fmt.Errorf("corrupt checkpoint: %w", err)
+	}
+
+	model := NewMarkovModel(MarkovConfig{})
+	if err := model.Load(cp.Model); err != nil {
+		return nil, fmt.Errorf("corrupt checkpoint model: %w", err)
+	}
+	model.resume = &cp
+	return model, nil
+}
+
+// trainWithCheckpoints trains in batches, writing a checkpoint whenever the
+// configured interval has passed since the previous one
+func (m *MarkovModel) trainWithCheckpoints(words []string, last int, o *trainOptions) error {
+	digest := digestTokens(words)
+	start := 0
+	if cp := m.resume; cp != nil {
+		if cp.Corpus != digest {
+			return fmt.Errorf("checkpoint was written for a different corpus")
+		}
+		start = cp.Offset
+		m.resume = nil
+	}
+
+	batch := 4096 * runtime.GOMAXPROCS(0)
+	lastWrite := time.Now()
+	for i := start; i < last; i += batch {
+		end := i + batch
+		if end > last {
+			end = last
+		}
+		m.trainRange(words, i, end)
+
+		if end < last && time.Since(lastWrite) >= o.checkpointEvery {
+			if err := m.writeCheckpoint(o.checkpointDir, end, digest); err != nil {
+				return err
+			}
+			lastWrite = time.Now()
+		}
+	}
+
+	// Training finished, so a stale checkpoint must not be resumed
+	if err := os.Remove(filepath.Join(o.checkpointDir, checkpointFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (m *MarkovModel) writeCheckpoint(dir string, offset int, digest uint64) error {
+	model, err := m.Save()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot model: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(checkpoint{Model: model, Offset: offset, Corpus: digest}); err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	return SaveModelToFile(buf.Bytes(), filepath.Join(dir, checkpointFile))
+}
+
+// digestTokens fingerprints a token stream so resumes can detect a changed corpus
+func digestTokens(words []string) uint64 {
+	h := fnv.New64a()
+	for _, w := range words {
+		h.Write([]byte(w))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
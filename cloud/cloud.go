@@ -0,0 +1,115 @@
+// Package cloud loads gophertext models and training corpora from object
+// storage, so serverless deployments can pull models at cold start instead
+// of bundling them into the binary. It only depends on the standard
+// library; S3 requests are signed with AWS Signature Version 4 and GCS
+// requests use an OAuth2 bearer token.
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jasonlovesdoggo/gophertext"
+)
+
+// BlobStore is the minimal object storage interface used by the loaders
+type BlobStore interface {
+	// Get opens the object stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns the keys of every object whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// LoadModelURL downloads and loads a serialized model. Supported schemes are
+// s3://bucket/key and gs://bucket/key (credentials from the environment)
+// and plain http:// or https:// URLs.
+func LoadModelURL(ctx context.Context, rawURL string) (*gophertext.MarkovModel, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model URL: %w", err)
+	}
+
+	var body io.ReadCloser
+	switch u.Scheme {
+	case "s3":
+		body, err = NewS3FromEnv(u.Host).Get(ctx, strings.TrimPrefix(u.Path, "/"))
+	case "gs":
+		body, err = NewGCSFromEnv(u.Host).Get(ctx, strings.TrimPrefix(u.Path, "/"))
+	case "http", "https":
+		body, err = httpGet(ctx, http.DefaultClient, rawURL, nil)
+	default:
+		return nil, fmt.Errorf("unsupported model URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download model: %w", err)
+	}
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{})
+	if err := model.Load(data); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// LoadCorpusObjectStore concatenates every .txt object under prefix, in key
+// order, the same way LoadTextDir does for a local directory
+func LoadCorpusObjectStore(ctx context.Context, store BlobStore, prefix string) (string, error) {
+	keys, err := store.List(ctx, prefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to list corpus objects: %w", err)
+	}
+	sort.Strings(keys)
+
+	var corpus strings.Builder
+	for _, key := range keys {
+		if path.Ext(key) != ".txt" {
+			continue
+		}
+		body, err := store.Get(ctx, key)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(&corpus, body)
+		body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		corpus.WriteString("\n")
+	}
+	return corpus.String(), nil
+}
+
+func httpGet(ctx context.Context, client *http.Client, rawURL string, prepare func(*http.Request)) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if prepare != nil {
+		prepare(req)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s: %s", req.URL.Redacted(), resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
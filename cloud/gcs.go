@@ -0,0 +1,108 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+const (
+	gcsAPI           = "https://storage.googleapis.com/storage/v1/b/"
+	gcsMetadataToken = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCS reads objects from a Google Cloud Storage bucket
+type GCS struct {
+	Bucket string
+	Token  string // OAuth2 access token; fetched from the metadata server when empty
+	Client *http.Client
+
+	once sync.Once
+}
+
+// NewGCSFromEnv creates a GCS store using GOOGLE_OAUTH_ACCESS_TOKEN when set
+func NewGCSFromEnv(bucket string) *GCS {
+	return &GCS{Bucket: bucket, Token: os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")}
+}
+
+// Get implements BlobStore
+func (g *GCS) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	u := gcsAPI + url.PathEscape(g.Bucket) + "/o/" + url.PathEscape(key) + "?alt=media"
+	return httpGet(ctx, g.Client, u, g.authorize(ctx))
+}
+
+// List implements BlobStore
+func (g *GCS) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"prefix": {prefix}, "fields": {"items(name),nextPageToken"}}
+		if token != "" {
+			query.Set("pageToken", token)
+		}
+		u := gcsAPI + url.PathEscape(g.Bucket) + "/o?" + query.Encode()
+		body, err := httpGet(ctx, g.Client, u, g.authorize(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(body).Decode(&page)
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid list response: %w", err)
+		}
+
+		for _, item := range page.Items {
+			keys = append(keys, item.Name)
+		}
+		if page.NextPageToken == "" {
+			return keys, nil
+		}
+		token = page.NextPageToken
+	}
+}
+
+// authorize returns a request hook adding the bearer token. Without a
+// configured token it asks the metadata server once, falling back to
+// anonymous access for public buckets.
+func (g *GCS) authorize(ctx context.Context) func(*http.Request) {
+	g.once.Do(func() {
+		if g.Token == "" {
+			g.Token = metadataToken(ctx, g.Client)
+		}
+	})
+	return func(req *http.Request) {
+		if g.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.Token)
+		}
+	}
+}
+
+func metadataToken(ctx context.Context, client *http.Client) string {
+	body, err := httpGet(ctx, client, gcsMetadataToken, func(req *http.Request) {
+		req.Header.Set("Metadata-Flavor", "Google")
+	})
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(body).Decode(&token); err != nil {
+		return ""
+	}
+	return token.AccessToken
+}
@@ -0,0 +1,135 @@
+// Command gophertext inspects, exports and generates from trained GopherText
+// models.
+//
+// Usage:
+//
+//	gophertext inspect [--dead-ends] model.gt
+//	gophertext export [--graph n] model.gt > model.json
+//	gophertext generate [--words n] [--width n] model.gt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jasonlovesdoggo/gophertext"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "inspect":
+		if err := inspect(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "gophertext:", err)
+			os.Exit(1)
+		}
+	case "generate":
+		if err := generate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "gophertext:", err)
+			os.Exit(1)
+		}
+	case "export":
+		if err := export(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "gophertext:", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gophertext inspect [--dead-ends] model.gt")
+	fmt.Fprintln(os.Stderr, "       gophertext export [--graph n] model.gt")
+	fmt.Fprintln(os.Stderr, "       gophertext generate [--words n] [--width n] model.gt")
+}
+
+func inspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	deadEnds := fs.Bool("dead-ends", false, "list prefixes with no continuations")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	model, err := loadModel(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *deadEnds {
+		ends := model.DeadEnds()
+		for _, prefix := range ends {
+			fmt.Println(prefix)
+		}
+		fmt.Printf("\n%d dead-end prefixes\n", len(ends))
+		fmt.Printf("expected fallback frequency: %.2f%% of steps\n", model.DeadEndRate()*100)
+	}
+	return nil
+}
+
+// export writes the model's chain to stdout as sorted JSON, or its
+// heaviest transitions as a node-link graph
+func export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	graph := fs.Int("graph", 0, "export the n heaviest transitions as a D3 graph")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	model, err := loadModel(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *graph > 0 {
+		return model.ExportJSONGraph(os.Stdout, gophertext.GraphOptions{MaxEdges: *graph})
+	}
+	return model.ExportJSON(os.Stdout)
+}
+
+// generate prints generated text, wrapped for the terminal
+func generate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	words := fs.Int("words", 100, "number of words to generate")
+	width := fs.Int("width", 80, "column to wrap at")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	model, err := loadModel(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	text, err := model.Generate(*words)
+	if err != nil {
+		return err
+	}
+	return gophertext.Render(os.Stdout, text, gophertext.RenderOptions{
+		Width:                 *width,
+		Color:                 os.Getenv("NO_COLOR") == "",
+		SentencesPerParagraph: 5,
+	})
+}
+
+func loadModel(path string) (*gophertext.MarkovModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model: %w", err)
+	}
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{})
+	if err := model.Load(data); err != nil {
+		return nil, fmt.Errorf("failed to load model: %w", err)
+	}
+	return model, nil
+}
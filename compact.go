@@ -0,0 +1,67 @@
+package gophertext
+
+import (
+	"fmt"
+)
+
+// CompactReport summarizes what Compact removed
+type CompactReport struct {
+	Transitions int // Suffix occurrences removed because they led nowhere
+	Prefixes    int // Prefixes removed because none of their transitions led anywhere
+	Passes      int // Sweeps needed to reach a fixed point
+}
+
+// Compact removes transitions into dead ends: a transition P -> w is dropped
+// when the prefix it moves to has no continuations of its own. Removing a
+// transition can leave its prefix empty, creating new dead ends, so Compact
+// sweeps until nothing changes. Pruning and redaction tend to leave such
+// chains behind, and each one costs generation a jump to a random prefix.
+// A model whose every path eventually dead-ends is left untouched.
+func (m *MarkovModel) Compact() (CompactReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var report CompactReport
+	alive := make(map[string]map[string]int, len(m.chain))
+	for prefix, suffixes := range m.chain {
+		alive[prefix] = suffixes
+	}
+
+	for changed := true; changed; {
+		changed = false
+		report.Passes++
+		for prefix, suffixes := range alive {
+			tail := splitKey(prefix)[1:]
+			kept := make(map[string]int, len(suffixes))
+			removed := 0
+			for s, n := range suffixes {
+				if _, ok := alive[joinKey(append(tail, s))]; ok {
+					kept[s] = n
+				} else {
+					removed += n
+				}
+			}
+			if removed == 0 {
+				continue
+			}
+			changed = true
+			report.Transitions += removed
+			if len(kept) == 0 {
+				delete(alive, prefix)
+				report.Prefixes++
+			} else {
+				alive[prefix] = kept
+			}
+		}
+	}
+
+	if len(alive) == 0 {
+		return CompactReport{}, fmt.Errorf("compaction would remove every prefix")
+	}
+	m.chain = alive
+	m.invalidateIndex()
+	if m.config.MaxMemoryBytes > 0 {
+		m.chainBytes = estimateChainBytes(m.chain)
+	}
+	return report, nil
+}
@@ -0,0 +1,90 @@
+package gophertext
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentUse runs training, generation, Save, Load, Merge and the
+// sentence splitter on one model at once. Run it with -race.
+func TestConcurrentUse(t *testing.T) {
+	corpus := testCorpus(t)
+	paragraphs := strings.Split(corpus, "\n\n")
+	cfg := MarkovConfig{Order: 2}
+
+	m := NewMarkovModel(cfg)
+	if err := m.BuildModel(corpus); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := m.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := NewMarkovModel(cfg)
+	if err := other.BuildModel(corpus); err != nil {
+		t.Fatal(err)
+	}
+
+	const rounds = 8
+	var wg sync.WaitGroup
+	run := func(name string, f func(i int) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				if err := f(i); err != nil {
+					t.Errorf("%s: %v", name, err)
+					return
+				}
+			}
+		}()
+	}
+
+	run("BuildModel", func(i int) error {
+		return m.BuildModel(paragraphs[i%len(paragraphs)])
+	})
+	for range 4 {
+		run("Generate", func(int) error {
+			_, err := m.Generate(40)
+			return err
+		})
+	}
+	run("GenerateFrom", func(int) error {
+		_, err := m.GenerateFrom("the old", 20, WithFallback(FallbackBackoff))
+		return err
+	})
+	run("Save", func(int) error {
+		_, err := m.Save()
+		return err
+	})
+	run("Load", func(int) error {
+		return m.Load(saved)
+	})
+	run("Merge", func(int) error {
+		return m.Merge(other)
+	})
+	run("Merge into other", func(int) error {
+		return other.Merge(m)
+	})
+	run("AddAbbreviations", func(i int) error {
+		m.AddAbbreviations(fmt.Sprintf("abbr%d", i))
+		return nil
+	})
+	run("SplitSentences", func(int) error {
+		if len(m.SplitSentences(paragraphs[0])) == 0 {
+			return fmt.Errorf("no sentences")
+		}
+		return nil
+	})
+	run("Language", func(int) error {
+		m.Language()
+		return nil
+	})
+	wg.Wait()
+
+	if err := m.validate(); err != nil {
+		t.Fatal(err)
+	}
+}
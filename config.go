@@ -0,0 +1,102 @@
+package gophertext
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Config returns a copy of the model's configuration, including one restored
+// by Load.
+//
+// Fields that shape tokenization are fixed once a model is trained:
+// changing Order, PreserveCase, EntityMode, Placeholders.Enabled,
+// MaxVocabulary or NoveltyN would make new text disagree with the chain.
+// Generation settings such as MaxSentenceLen and ParagraphBreak can be
+// changed at any time with their setters.
+func (m *MarkovModel) Config() MarkovConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg := m.config
+	cfg.Abbreviations = slices.Clone(cfg.Abbreviations)
+	cfg.Acronyms = slices.Clone(cfg.Acronyms)
+	cfg.ForbiddenStarters = slices.Clone(cfg.ForbiddenStarters)
+	cfg.ForbiddenEnders = slices.Clone(cfg.ForbiddenEnders)
+	cfg.Constraints = maps.Clone(cfg.Constraints)
+	return cfg
+}
+
+// SetMaxSentenceLen changes how many words a generated sentence may run
+// before a period is forced (0 = unlimited)
+func (m *MarkovModel) SetMaxSentenceLen(n int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n < 0 {
+		return fmt.Errorf("invalid maximum sentence length %d", n)
+	}
+	if n > 0 && n < m.config.MinSentenceLen {
+		return fmt.Errorf("maximum sentence length %d is below the minimum %d", n, m.config.MinSentenceLen)
+	}
+	m.config.MaxSentenceLen = n
+	return nil
+}
+
+// SetParagraphBreak changes how many sentences a generated paragraph
+// typically holds (0 = no paragraph breaks)
+func (m *MarkovModel) SetParagraphBreak(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid paragraph break %d", n)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.ParagraphBreak = n
+	return nil
+}
+
+// SetMaxRepeat changes how often a word may repeat back to back before the
+// repeat is replaced
+func (m *MarkovModel) SetMaxRepeat(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid maximum repeat %d", n)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.MaxRepeat = n
+	return nil
+}
+
+// SetEndingBias changes how strongly generation steers toward a sentence
+// end as it nears the requested length (0 = off)
+func (m *MarkovModel) SetEndingBias(bias float64) error {
+	if bias < 0 {
+		return fmt.Errorf("invalid ending bias %v", bias)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.EndingBias = bias
+	return nil
+}
+
+// SetPronounConsistency changes how strongly English generation avoids
+// switching between "I", "we", "you" and "he"/"she"/"they" within a
+// sentence (0 = off)
+func (m *MarkovModel) SetPronounConsistency(strength float64) error {
+	if strength < 0 {
+		return fmt.Errorf("invalid pronoun consistency %v", strength)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.PronounConsistency = strength
+	return nil
+}
+
+// SetOutputPolicy changes the punctuation and whitespace of generated text
+func (m *MarkovModel) SetOutputPolicy(policy OutputPolicy) error {
+	if policy.SentenceSpacing < 0 {
+		return fmt.Errorf("invalid sentence spacing %d", policy.SentenceSpacing)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Output = policy
+	return nil
+}
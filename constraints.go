@@ -0,0 +1,163 @@
+package gophertext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxBacktracks bounds how often constrained generation may undo a word
+const maxBacktracks = 1000
+
+// ErrUnsatisfiable is returned when constrained generation cannot find a
+// continuation that respects its constraints
+var ErrUnsatisfiable = errors.New("constraints cannot be satisfied")
+
+// ConstraintSet lists what generated words may not contain. Matching is
+// case-insensitive, so ExcludeChars "e" produces a lipogram without "e" or "E".
+type ConstraintSet struct {
+	ExcludeChars      string   // Characters no word may contain
+	ExcludeSubstrings []string // Substrings no word may contain
+}
+
+// Allows reports whether word satisfies the constraint set
+func (c ConstraintSet) Allows(word string) bool {
+	word = strings.ToLower(word)
+	if strings.ContainsAny(word, strings.ToLower(c.ExcludeChars)) {
+		return false
+	}
+	for _, s := range c.ExcludeSubstrings {
+		if s != "" && strings.Contains(word, strings.ToLower(s)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c ConstraintSet) empty() bool {
+	return c.ExcludeChars == "" && len(c.ExcludeSubstrings) == 0
+}
+
+// DefineConstraint stores a named constraint set in the model configuration,
+// so it is saved with the model and can be selected with WithConstraint
+func (m *MarkovModel) DefineConstraint(name string, set ConstraintSet) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Constraints == nil {
+		m.config.Constraints = make(map[string]ConstraintSet)
+	}
+	m.config.Constraints[name] = set
+}
+
+// WithConstraint applies the named constraint set stored on the model.
+// Generation fails if no set of that name was defined.
+func WithConstraint(name string) GenerateOption {
+	return func(o *generateOptions) {
+		o.constraintName = name
+	}
+}
+
+// WithExclusions applies an ad-hoc constraint set to one generation
+func WithExclusions(set ConstraintSet) GenerateOption {
+	return func(o *generateOptions) {
+		o.constraints = set
+	}
+}
+
+// generateConstrained walks the chain like generate, but never emits a word
+// the constraint set or exclusions reject. When every continuation is rejected it
+// backtracks, banning the previous word at that position and resampling.
+func (m *MarkovModel) generateConstrained(wordCount int, o *generateOptions) (string, GenerationStats, error) {
+	var stats GenerationStats
+
+	tokens := append([]string(nil), o.seed...)
+	if len(tokens) == 0 {
+		start, ok := m.allowedPrefix(o)
+		if !ok {
+			return "", stats, fmt.Errorf("%w: no prefix satisfies them", ErrUnsatisfiable)
+		}
+		tokens = splitKey(start)
+	} else {
+		wordCount += len(tokens)
+	}
+	for i, t := range tokens {
+		if !o.allows(tokens[:i], t) {
+			return "", stats, fmt.Errorf("%w: prompt word %q is excluded", ErrUnsatisfiable, t)
+		}
+	}
+
+	fixed := len(tokens)
+	banned := make(map[int]map[string]bool)
+	backtracks := 0
+	for len(tokens) < wordCount {
+		context := tokens
+		if len(context) > m.config.Order {
+			context = context[len(context)-m.config.Order:]
+		}
+		key := joinKey(context)
+
+		allowed := make(map[string]int)
+		for s, n := range m.chain[key] {
+			if o.allows(tokens, s) && !banned[len(tokens)][s] {
+				allowed[s] = n
+			}
+		}
+		m.noteSentence(tokens, o)
+		m.noteRemaining(wordCount-len(tokens), o)
+		if next, ok := m.sample(key, allowed, o); ok {
+			tokens = append(tokens, next)
+			continue
+		}
+
+		if backtracks == maxBacktracks || (len(tokens) == fixed && len(o.seed) > 0) {
+			return "", stats, fmt.Errorf("%w after %d words", ErrUnsatisfiable, len(tokens))
+		}
+		backtracks++
+		stats.Fallbacks++
+		if len(tokens) == fixed {
+			// A random start that leads nowhere is replaced
+			start, _ := m.allowedPrefix(o)
+			tokens = splitKey(start)
+			banned = make(map[int]map[string]bool)
+			continue
+		}
+		delete(banned, len(tokens))
+		last := tokens[len(tokens)-1]
+		tokens = tokens[:len(tokens)-1]
+		if banned[len(tokens)] == nil {
+			banned[len(tokens)] = make(map[string]bool)
+		}
+		banned[len(tokens)][last] = true
+	}
+
+	stats.Words = len(tokens)
+	stats.tokens = tokens
+	return m.formatTokens(tokens, o), stats, nil
+}
+
+// allowedPrefix picks a random prefix whose words all satisfy o
+func (m *MarkovModel) allowedPrefix(o *generateOptions) (string, bool) {
+	allows := func(prefix string) bool {
+		if hasUnknown(prefix) {
+			return false
+		}
+		words := splitKey(prefix)
+		for i, w := range words {
+			if !o.allows(words[:i], w) {
+				return false
+			}
+		}
+		return true
+	}
+	for attempt := 0; attempt < 100; attempt++ {
+		if prefix := m.startPrefix(); allows(prefix) {
+			return prefix, true
+		}
+	}
+	for _, prefix := range m.prefixIndex().prefixes {
+		if allows(prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package gophertext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// CooccurrenceMatrix counts how often two words appear within Window words
+// of each other in the training text. Pass it to BuildModel with
+// WithCooccurrence to fill it from the same token stream that trains the
+// chain. The matrix is symmetric and sparse: only pairs that occur are kept.
+type CooccurrenceMatrix struct {
+	Window int // Largest distance in words between counted pairs
+
+	mu     sync.Mutex
+	counts map[wordPair]int
+}
+
+// wordPair is an unordered pair of words, stored with a <= b
+type wordPair struct {
+	a, b string
+}
+
+// NewCooccurrenceMatrix creates an empty matrix counting pairs up to window
+// words apart
+func NewCooccurrenceMatrix(window int) *CooccurrenceMatrix {
+	if window < 1 {
+		window = 1
+	}
+	return &CooccurrenceMatrix{
+		Window: window,
+		counts: make(map[wordPair]int),
+	}
+}
+
+// WithCooccurrence counts word co-occurrences into c while training
+func WithCooccurrence(c *CooccurrenceMatrix) TrainOption {
+	return func(o *trainOptions) {
+		o.cooccurrence = c
+	}
+}
+
+// add counts the pairs within the window in a token stream
+func (c *CooccurrenceMatrix) add(words []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, w := range words {
+		for j := i + 1; j < len(words) && j <= i+c.Window; j++ {
+			c.counts[newWordPair(w, words[j])]++
+		}
+	}
+}
+
+func newWordPair(a, b string) wordPair {
+	if b < a {
+		a, b = b, a
+	}
+	return wordPair{a, b}
+}
+
+// Count returns how often a and b appeared within the window of each other
+func (c *CooccurrenceMatrix) Count(a, b string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[newWordPair(a, b)]
+}
+
+// Export writes the matrix as tab-separated "word, word, count" lines,
+// sorted by the first word and then the second. Each unordered pair
+// appears once, with the words in byte order.
+func (c *CooccurrenceMatrix) Export(w io.Writer) error {
+	c.mu.Lock()
+	pairs := make([]wordPair, 0, len(c.counts))
+	for p := range c.counts {
+		pairs = append(pairs, p)
+	}
+	counts := make([]int, len(pairs))
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].a != pairs[j].a {
+			return pairs[i].a < pairs[j].a
+		}
+		return pairs[i].b < pairs[j].b
+	})
+	for i, p := range pairs {
+		counts[i] = c.counts[p]
+	}
+	c.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for i, p := range pairs {
+		if _, err := fmt.Fprintf(bw, "%s\t%s\t%d\n", p.a, p.b, counts[i]); err != nil {
+			return fmt.Errorf("failed to write co-occurrence matrix: %w", err)
+		}
+	}
+	return bw.Flush()
+}
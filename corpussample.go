@@ -0,0 +1,53 @@
+package gophertext
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// SampleCorpus keeps a random fraction of the sentences of text, in their
+// original order, for training a quick prototype before committing to the
+// full corpus. The same seed always picks the same sentences.
+func SampleCorpus(text string, fraction float64, seed int64) string {
+	sentences := NewSentenceSplitter("").Split(text)
+	rng := rand.New(rand.NewSource(seed))
+	return strings.Join(sampleSentences(sentences, fraction, rng, false), " ")
+}
+
+// SampleDocuments is SampleCorpus stratified by document: the same fraction
+// of sentences is drawn from every document, and each non-empty document
+// keeps at least one, so small documents aren't crowded out by large ones.
+// Documents keep their positions in the result.
+func SampleDocuments(docs []string, fraction float64, seed int64) []string {
+	splitter := NewSentenceSplitter("")
+	rng := rand.New(rand.NewSource(seed))
+	sampled := make([]string, len(docs))
+	for i, doc := range docs {
+		sampled[i] = strings.Join(sampleSentences(splitter.Split(doc), fraction, rng, true), " ")
+	}
+	return sampled
+}
+
+// sampleSentences picks round(fraction * len(sentences)) sentences at
+// random, at least one when keepOne is set, and returns them in order
+func sampleSentences(sentences []string, fraction float64, rng *rand.Rand, keepOne bool) []string {
+	if fraction <= 0 || len(sentences) == 0 {
+		return nil
+	}
+	if fraction >= 1 {
+		return sentences
+	}
+	n := int(math.Round(fraction * float64(len(sentences))))
+	if n == 0 && keepOne {
+		n = 1
+	}
+	picked := rng.Perm(len(sentences))[:n]
+	sort.Ints(picked)
+	kept := make([]string, n)
+	for i, idx := range picked {
+		kept[i] = sentences[idx]
+	}
+	return kept
+}
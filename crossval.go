@@ -0,0 +1,78 @@
+package gophertext
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Fold is one train/held-out partition of a corpus
+type Fold struct {
+	Train   string
+	HeldOut string
+}
+
+// KFold splits text into k contiguous blocks of sentences and returns k
+// folds, each holding out one block and training on the rest. Contiguous
+// blocks keep a story's sentences together, so a fold cannot be predicted
+// from its immediate neighbours.
+func KFold(text string, k int) ([]Fold, error) {
+	if k < 2 {
+		return nil, fmt.Errorf("k-fold cross-validation needs k >= 2, got %d", k)
+	}
+	sentences := NewSentenceSplitter("").Split(text)
+	if len(sentences) < k {
+		return nil, fmt.Errorf("corpus has %d sentences, too few for %d folds", len(sentences), k)
+	}
+
+	folds := make([]Fold, k)
+	for i := range folds {
+		from, to := i*len(sentences)/k, (i+1)*len(sentences)/k
+		train := make([]string, 0, len(sentences)-(to-from))
+		train = append(train, sentences[:from]...)
+		train = append(train, sentences[to:]...)
+		folds[i] = Fold{
+			Train:   strings.Join(train, " "),
+			HeldOut: strings.Join(sentences[from:to], " "),
+		}
+	}
+	return folds, nil
+}
+
+// CVResult summarizes a cross-validation run
+type CVResult struct {
+	Scores []float64 // Score of each fold
+	Mean   float64
+	StdDev float64
+}
+
+// CrossValidate trains cfg on each of k folds of corpus and scores it on the
+// held-out block with eval (PerplexityEval if nil). Comparing the means and
+// spreads of two configurations is far less sensitive to a lucky split than
+// a single SplitCorpus run on a small corpus.
+func CrossValidate(corpus string, cfg MarkovConfig, k int, eval EvalFunc) (CVResult, error) {
+	if eval == nil {
+		eval = PerplexityEval
+	}
+	folds, err := KFold(corpus, k)
+	if err != nil {
+		return CVResult{}, err
+	}
+
+	var result CVResult
+	for i, fold := range folds {
+		model := NewMarkovModel(cfg)
+		if err := model.BuildModel(fold.Train); err != nil {
+			return CVResult{}, fmt.Errorf("fold %d: %w", i, err)
+		}
+		score := eval(model, fold.HeldOut)
+		result.Scores = append(result.Scores, score)
+		result.Mean += score
+	}
+	result.Mean /= float64(k)
+	for _, s := range result.Scores {
+		result.StdDev += (s - result.Mean) * (s - result.Mean)
+	}
+	result.StdDev = math.Sqrt(result.StdDev / float64(k))
+	return result, nil
+}
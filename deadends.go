@@ -0,0 +1,53 @@
+package gophertext
+
+import (
+	"sort"
+)
+
+// DeadEnds lists, in sorted order, the prefixes that training produced as
+// successors but that have no continuations. Generation reaching one of
+// them has to jump to a random prefix, which reads as a change of topic.
+func (m *MarkovModel) DeadEnds() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for prefix, suffixes := range m.chain {
+		tail := splitKey(prefix)[1:]
+		for s := range suffixes {
+			next := joinKey(append(tail, s))
+			if _, ok := m.chain[next]; !ok {
+				seen[next] = true
+			}
+		}
+	}
+
+	ends := make([]string, 0, len(seen))
+	for prefix := range seen {
+		ends = append(ends, prefix)
+	}
+	sort.Strings(ends)
+	return ends
+}
+
+// DeadEndRate estimates how often a generation step falls back to a random
+// prefix: the share of all transition occurrences that lead into a dead end
+func (m *MarkovModel) DeadEndRate() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	total, dead := 0, 0
+	for prefix, suffixes := range m.chain {
+		tail := splitKey(prefix)[1:]
+		for s, n := range suffixes {
+			total += n
+			if _, ok := m.chain[joinKey(append(tail, s))]; !ok {
+				dead += n
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(dead) / float64(total)
+}
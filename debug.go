@@ -0,0 +1,27 @@
+package gophertext
+
+// Generation rules reported in TraceStep.Rule
+const (
+	RuleRepeat      = "repeat"       // A word repeated more than MaxRepeat times was replaced
+	RuleSentenceEnd = "sentence-end" // The previous word ended a sentence, so this one was capitalized
+	RuleMaxLength   = "max-length"   // MaxSentenceLen forced a sentence break before this word
+)
+
+// TraceStep describes one generated word
+type TraceStep struct {
+	Prefix     string // Prefix the word was sampled from
+	Candidates int    // Suffix occurrences stored for that prefix
+	Fallback   bool   // The previous prefix dead-ended and Prefix was picked at random
+	Word       string // Word as written to the output, after rules
+	Rule       string // Generation rule that changed the word, if any
+}
+
+// GenerateDebug is Generate, additionally returning a trace of every step:
+// the prefix consulted, how many candidates it offered, and whether a
+// fallback or generation rule fired. Use it to find where output derails.
+func (m *MarkovModel) GenerateDebug(wordCount int, opts ...GenerateOption) (string, []TraceStep, error) {
+	o := newGenerateOptions(opts)
+	o.debug = true
+	text, stats, err := m.generate(wordCount, o)
+	return text, stats.trace, err
+}
@@ -0,0 +1,62 @@
+package gophertext
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Decay ages the model by the time elapsed since it was last trained or
+// decayed: every transition count is scaled by 0.5^(elapsed/halfLife).
+// Fresh training then adds full-weight transitions, so a model that is
+// decayed and retrained regularly emphasizes recent material. Fractional
+// counts are rounded stochastically, keeping expected counts exact, and
+// transitions that round to zero are forgotten.
+func (m *MarkovModel) Decay(halfLife time.Duration) {
+	if halfLife <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if m.updated.IsZero() {
+		// Untimestamped (legacy) models start aging from now
+		m.updated = now
+		return
+	}
+	elapsed := now.Sub(m.updated)
+	m.updated = now
+	m.scaleCounts(math.Pow(0.5, float64(elapsed)/float64(halfLife)))
+}
+
+// scaleCounts multiplies every transition count by factor. Callers must
+// hold the write lock.
+func (m *MarkovModel) scaleCounts(factor float64) {
+	if factor >= 1 {
+		return
+	}
+	for prefix, suffixes := range m.chain {
+		for w, n := range suffixes {
+			if scaled := stochasticRound(float64(n)*factor, m.rng); scaled > 0 {
+				suffixes[w] = scaled
+			} else {
+				delete(suffixes, w)
+			}
+		}
+		if len(suffixes) == 0 {
+			delete(m.chain, prefix)
+		}
+	}
+	m.invalidateIndex()
+}
+
+// stochasticRound rounds x up with probability equal to its fractional part
+func stochasticRound(x float64, rng *rand.Rand) int {
+	whole := math.Floor(x)
+	if rng.Float64() < x-whole {
+		whole++
+	}
+	return int(whole)
+}
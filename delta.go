@@ -0,0 +1,163 @@
+package gophertext
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"strconv"
+	"time"
+)
+
+// modelDelta is the serialized difference between two models
+type modelDelta struct {
+	Base     uint64                    // chainDigest of the model the delta applies to
+	Config   MarkovConfig              // Configuration of the updated model
+	Updated  time.Time                 // Training timestamp of the updated model
+	Changes  map[string]map[string]int // Per-transition count changes (negative removes)
+	Negative map[string]map[string]int // Negative training of the updated model
+	Ngrams   []uint64                  // Corpus n-gram hashes new in the updated model
+	Vocab    map[string]bool           // Vocabulary of the updated model
+	Casing   map[string]caseCount      // Casing statistics of the updated model
+}
+
+// SaveDelta serializes the changes that turn base into updated, so retrained
+// models can be shipped to deployments as a small patch instead of a full
+// model file. Both models must tokenize the same way.
+func SaveDelta(base, updated *MarkovModel) ([]byte, error) {
+	if err := compatibleConfigs(base.settings(), updated.settings()); err != nil {
+		return nil, err
+	}
+
+	// Copy updated first instead of holding both locks, so SaveDelta(a, b)
+	// and SaveDelta(b, a) running together can't deadlock
+	updated.mu.RLock()
+	delta := modelDelta{
+		Config:   updated.config,
+		Updated:  updated.updated,
+		Changes:  make(map[string]map[string]int),
+		Negative: cloneChain(updated.negative),
+		Vocab:    maps.Clone(updated.vocab),
+		Casing:   maps.Clone(updated.casing),
+	}
+	chain := cloneChain(updated.chain)
+	ngrams := maps.Clone(updated.ngrams)
+	updated.mu.RUnlock()
+
+	base.mu.RLock()
+	defer base.mu.RUnlock()
+	delta.Base = base.chainDigest()
+	for h := range ngrams {
+		if !base.ngrams[h] {
+			delta.Ngrams = append(delta.Ngrams, h)
+		}
+	}
+	for prefix, suffixes := range chain {
+		changes := suffixes
+		for w, n := range base.chain[prefix] {
+			changes[w] -= n
+		}
+		delta.addChanges(prefix, changes)
+	}
+	for prefix, suffixes := range base.chain {
+		if _, ok := chain[prefix]; ok {
+			continue
+		}
+		changes := maps.Clone(suffixes)
+		for w, n := range changes {
+			changes[w] = -n
+		}
+		delta.addChanges(prefix, changes)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(delta); err != nil {
+		return nil, fmt.Errorf("failed to encode delta: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *modelDelta) addChanges(prefix string, changes map[string]int) {
+	for w, n := range changes {
+		if n == 0 {
+			delete(changes, w)
+		}
+	}
+	if len(changes) > 0 {
+		d.Changes[prefix] = changes
+	}
+}
+
+// ApplyDelta patches base in place with a delta written by SaveDelta. It
+// refuses deltas computed against a different base model.
+func ApplyDelta(base *MarkovModel, delta []byte) error {
+	var d modelDelta
+	if err := gob.NewDecoder(bytes.NewReader(delta)).Decode(&d); err != nil {
+		return fmt.Errorf("failed to decode delta: %w", err)
+	}
+
+	base.mu.Lock()
+	defer base.mu.Unlock()
+
+	if base.chainDigest() != d.Base {
+		return fmt.Errorf("delta was computed against a different base model")
+	}
+
+	// Patch a copy, so a delta that would leave an invalid model is
+	// rejected before anything changes
+	chain := maps.Clone(base.chain)
+	for prefix, changes := range d.Changes {
+		counts := maps.Clone(chain[prefix])
+		if counts == nil {
+			counts = make(map[string]int, len(changes))
+		}
+		for w, n := range changes {
+			if counts[w] += n; counts[w] <= 0 {
+				delete(counts, w)
+			}
+		}
+		if len(counts) == 0 {
+			delete(chain, prefix)
+		} else {
+			chain[prefix] = counts
+		}
+	}
+	if err := validateModel(d.Config, chain, d.Negative); err != nil {
+		return err
+	}
+
+	base.config = d.Config
+	base.chain = chain
+	base.updated = d.Updated
+	base.negative = d.Negative
+	base.vocab = d.Vocab
+	base.casing = d.Casing
+	for _, h := range d.Ngrams {
+		if base.ngrams == nil {
+			base.ngrams = make(map[uint64]bool)
+		}
+		base.ngrams[h] = true
+	}
+	base.restored()
+	return nil
+}
+
+// chainDigest fingerprints the transition counts independently of map and
+// slice order. Callers must hold at least the read lock.
+func (m *MarkovModel) chainDigest() uint64 {
+	var sum uint64
+	h := fnv.New64a()
+	for prefix, suffixes := range m.chain {
+		for w, n := range suffixes {
+			h.Reset()
+			h.Write([]byte(prefix))
+			h.Write([]byte{0})
+			h.Write([]byte(w))
+			h.Write([]byte{0})
+			h.Write([]byte(strconv.Itoa(n)))
+			sum += h.Sum64()
+		}
+	}
+	return sum
+}
@@ -0,0 +1,115 @@
+package gophertext
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestApplyDelta(t *testing.T) {
+	corpus := testCorpus(t)
+	half := len(corpus) / 2
+	cfg := MarkovConfig{Order: 2, Seed: 5, MaxTransitionWeight: 50}
+	base := NewMarkovModel(cfg)
+	if err := base.BuildModel(corpus[:half]); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := base.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := NewMarkovModel(cfg)
+	if err := updated.Load(saved); err != nil {
+		t.Fatal(err)
+	}
+	if err := updated.BuildModel(corpus[half:]); err != nil {
+		t.Fatal(err)
+	}
+	delta, err := SaveDelta(base, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := base.Generate(20); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyDelta(base, delta); err != nil {
+		t.Fatal(err)
+	}
+	if base.Fingerprint() != updated.Fingerprint() {
+		t.Fatal("patched model differs from the updated one")
+	}
+
+	// Like Load, applying a delta reseeds the model
+	data, err := updated.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewMarkovModel(MarkovConfig{})
+	if err := loaded.Load(data); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := base.Generate(20)
+	want, _ := loaded.Generate(20)
+	if got != want {
+		t.Errorf("patched model generated %q, loaded model %q", got, want)
+	}
+}
+
+func TestApplyInvalidDelta(t *testing.T) {
+	base := NewMarkovModel(MarkovConfig{Order: 2})
+	if err := base.BuildModel(testCorpus(t)); err != nil {
+		t.Fatal(err)
+	}
+	before := base.Fingerprint()
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(modelDelta{
+		Base:    base.chainDigest(),
+		Config:  base.Config(),
+		Changes: map[string]map[string]int{"three word prefix": {"next": 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyDelta(base, buf.Bytes()); err == nil || !strings.Contains(err.Error(), "prefix") {
+		t.Fatalf("ApplyDelta = %v, want an invalid prefix error", err)
+	}
+	if base.Fingerprint() != before {
+		t.Error("rejected delta changed the model")
+	}
+}
+
+func TestSaveDeltaBothWays(t *testing.T) {
+	corpus := testCorpus(t)
+	a, b := NewMarkovModel(MarkovConfig{Order: 2}), NewMarkovModel(MarkovConfig{Order: 2})
+	if err := a.BuildModel(corpus); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.BuildModel(corpus[:len(corpus)/2]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Writers waiting on both models make overlapping read locks deadlock
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(4)
+			go func() { defer wg.Done(); SaveDelta(a, b) }()
+			go func() { defer wg.Done(); SaveDelta(b, a) }()
+			go func() { defer wg.Done(); a.BuildModel("the end") }()
+			go func() { defer wg.Done(); b.BuildModel("the end") }()
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		t.Fatal("SaveDelta deadlocked")
+	}
+}
@@ -0,0 +1,66 @@
+package gophertext
+
+// diversityPenalty divides a transition's weight by 1+diversityPenalty for
+// every earlier output of GenerateDiverse that already used it
+const diversityPenalty = 4.0
+
+// diversity tracks what earlier outputs of GenerateDiverse used
+type diversity struct {
+	used   map[transition]int // Transitions taken by earlier outputs
+	starts map[string]bool    // Prefixes earlier outputs started from
+}
+
+// GenerateDiverse generates count texts of wordCount words each, steering
+// every output away from the transitions and starting prefixes of the
+// outputs before it, so a batch does not read alike. The batch as a whole
+// is held to MaxWordCount words.
+func (m *MarkovModel) GenerateDiverse(count, wordCount int, opts ...GenerateOption) ([]string, error) {
+	if err := m.checkBatch(count, wordCount); err != nil {
+		return nil, err
+	}
+	d := &diversity{
+		used:   make(map[transition]int),
+		starts: make(map[string]bool),
+	}
+	order := m.settings().Order
+	texts := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		o := newGenerateOptions(opts)
+		o.diversity = d
+		text, stats, err := m.generate(wordCount, o)
+		if err != nil {
+			return nil, err
+		}
+		d.record(stats.tokens, order)
+		texts = append(texts, text)
+	}
+	return texts, nil
+}
+
+// record adds the transitions of tokens to d
+func (d *diversity) record(tokens []string, order int) {
+	if len(tokens) < order {
+		return
+	}
+	d.starts[joinKey(tokens[:order])] = true
+	for i := order; i < len(tokens); i++ {
+		d.used[transition{joinKey(tokens[i-order : i]), tokens[i]}]++
+	}
+}
+
+// weight scales the sampling weight of suffix after prefix
+func (d *diversity) weight(prefix, suffix string, weight float64) float64 {
+	if n := d.used[transition{prefix, suffix}]; n > 0 {
+		weight /= 1 + diversityPenalty*float64(n)
+	}
+	return weight
+}
+
+// diverseStart picks a random start prefix, preferring one no earlier output used
+func (m *MarkovModel) diverseStart(d *diversity) string {
+	prefix := m.startPrefix()
+	for attempt := 0; attempt < 20 && d.starts[prefix]; attempt++ {
+		prefix = m.startPrefix()
+	}
+	return prefix
+}
@@ -0,0 +1,148 @@
+package gophertext
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// dryRunTop is how many of the most frequent transitions a dry run reports
+const dryRunTop = 20
+
+// DryRunReport describes the model a training run would build
+type DryRunReport struct {
+	Order       int               // Order the model would use
+	Tokens      int               // Tokens after normalization
+	Vocabulary  int               // Distinct tokens
+	Transitions int               // Transitions counted
+	Prefixes    int               // Distinct prefixes
+	Distinct    int               // Distinct transitions
+	ChainBytes  int64             // Projected heap used by the chain
+	Top         []TransitionCount // Most frequent transitions, most frequent first
+}
+
+// TransitionCount is a transition and how often it occurs
+type TransitionCount struct {
+	Prefix string
+	Word   string
+	Count  int // Estimated from a count-min sketch, so it may run slightly high
+}
+
+// WithDryRun makes BuildModel tokenize and count the corpus into report
+// instead of training. The model is left unchanged and no chain is built:
+// prefixes and transitions are tracked as hashes and their counts in a
+// fixed-size sketch, so even huge corpora can be checked quickly.
+func WithDryRun(report *DryRunReport) TrainOption {
+	return func(o *trainOptions) {
+		o.dryRun = report
+	}
+}
+
+// previewTokens tokenizes text as training would without fixing the
+// vocabulary, so a dry run leaves the model unchanged
+func (m *MarkovModel) previewTokens(text string) []string {
+	words := m.rawTokens(text)
+	cfg := m.settings()
+	if cfg.MaxVocabulary == 0 {
+		return words
+	}
+	m.mu.RLock()
+	vocab := m.vocab
+	m.mu.RUnlock()
+	if vocab == nil {
+		vocab = topWords(words, cfg.MaxVocabulary)
+	}
+	return capWords(words, vocab)
+}
+
+// dryRun fills the report of o with the statistics of training on words
+func (m *MarkovModel) dryRun(words []string, o *trainOptions) {
+	report := o.dryRun
+	cfg := m.settings()
+	vocabulary := make(map[string]bool)
+	for _, w := range words {
+		vocabulary[w] = true
+	}
+	order := cfg.Order
+	if order == OrderAuto {
+		order = pickOrder(words, o)
+	}
+	*report = DryRunReport{Order: order, Tokens: len(words), Vocabulary: len(vocabulary)}
+
+	prefixes := make(map[uint64]bool)
+	transitions := make(map[uint64]bool)
+	sketch := newCountMinSketch(1<<16, 4)
+	candidates := make(map[transition]uint32)
+	var keyBytes, suffixWordBytes int64
+	for i := 0; i+order < len(words); i++ {
+		prefix := joinKey(words[i : i+order])
+		word := words[i+order]
+		report.Transitions++
+
+		h := fnv.New64a()
+		h.Write([]byte(prefix))
+		if p := h.Sum64(); !prefixes[p] {
+			prefixes[p] = true
+			keyBytes += int64(len(prefix))
+		}
+		h.Write([]byte{0})
+		h.Write([]byte(word))
+		if t := h.Sum64(); !transitions[t] {
+			transitions[t] = true
+			suffixWordBytes += int64(len(word))
+		}
+
+		sketch.add(prefix, word, 1)
+		candidates[transition{prefix, word}] = sketch.estimate(prefix, word)
+		if len(candidates) > dryRunTop*50 {
+			pruneCandidates(candidates, dryRunTop*10)
+		}
+	}
+	report.Prefixes = len(prefixes)
+	report.Distinct = len(transitions)
+	report.ChainBytes = int64(report.Prefixes)*prefixOverhead + keyBytes +
+		int64(report.Distinct)*suffixBytes + suffixWordBytes
+
+	for t, n := range candidates {
+		report.Top = append(report.Top, TransitionCount{Prefix: t.prefix, Word: t.suffix, Count: int(n)})
+	}
+	sort.Slice(report.Top, func(i, j int) bool {
+		a, b := report.Top[i], report.Top[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Prefix != b.Prefix {
+			return a.Prefix < b.Prefix
+		}
+		return a.Word < b.Word
+	})
+	if len(report.Top) > dryRunTop {
+		report.Top = report.Top[:dryRunTop]
+	}
+}
+
+// pruneCandidates keeps the n candidates with the highest counts
+func pruneCandidates(candidates map[transition]uint32, n int) {
+	if len(candidates) <= n {
+		return
+	}
+	counts := make([]uint32, 0, len(candidates))
+	for _, c := range candidates {
+		counts = append(counts, c)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i] > counts[j] })
+	threshold := counts[n-1]
+	for t, c := range candidates {
+		if c < threshold {
+			delete(candidates, t)
+		}
+	}
+	// Ties at the threshold may leave a few extra
+	for t, c := range candidates {
+		if len(candidates) <= n {
+			break
+		}
+		if c == threshold {
+			delete(candidates, t)
+		}
+	}
+}
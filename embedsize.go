@@ -0,0 +1,120 @@
+package gophertext
+
+import (
+	"fmt"
+	"sort"
+)
+
+// EmbedSizeLimit is the saved size above which embedding a model with
+// go:embed gets awkward: binaries bloat, builds slow down and most Git
+// hosts start rejecting or warning about the file
+const EmbedSizeLimit = 32 << 20
+
+// EmbedReport describes a model saved by SaveForEmbed
+type EmbedReport struct {
+	Bytes        int     // Size of the saved model
+	FullBytes    int     // Size the unpruned model saves to
+	Prefixes     int     // Prefixes kept
+	FullPrefixes int     // Prefixes in the unpruned model
+	Coverage     float64 // Share of transition occurrences kept
+	Warning      string  // Set when Bytes exceeds EmbedSizeLimit
+}
+
+// SaveForEmbed saves the model like Save, reporting its size and warning
+// when it exceeds EmbedSizeLimit. With maxBytes above zero, the least
+// frequent prefixes are pruned until the saved model fits, giving an
+// embeddable variant at the cost of some dead ends; the model itself is
+// left unchanged.
+func (m *MarkovModel) SaveForEmbed(maxBytes int) ([]byte, EmbedReport, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	meta := modelMeta{
+		Config:   m.config,
+		Updated:  m.updated,
+		Negative: m.negative,
+		Ngrams:   m.ngrams,
+		Vocab:    m.vocab,
+		Casing:   m.casing,
+	}
+	chain := m.savedChain()
+
+	data, err := encodeModel(meta, chain)
+	if err != nil {
+		return nil, EmbedReport{}, err
+	}
+	report := EmbedReport{FullBytes: len(data), FullPrefixes: len(chain)}
+
+	if maxBytes > 0 && len(data) > maxBytes {
+		prefixes, total := prefixesByFrequency(chain)
+		top := func(n int) map[string]map[string]int {
+			kept := make(map[string]map[string]int, n)
+			for _, prefix := range prefixes[:n] {
+				kept[prefix] = chain[prefix]
+			}
+			return kept
+		}
+
+		// Find the most prefixes that still fit
+		lo, hi := 0, len(prefixes)
+		var best []byte
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			candidate, err := encodeModel(meta, top(mid))
+			if err != nil {
+				return nil, EmbedReport{}, err
+			}
+			if len(candidate) <= maxBytes {
+				lo, best = mid, candidate
+			} else {
+				hi = mid - 1
+			}
+		}
+		if best == nil {
+			if best, err = encodeModel(meta, top(0)); err != nil {
+				return nil, EmbedReport{}, err
+			}
+			if len(best) > maxBytes {
+				return nil, EmbedReport{}, fmt.Errorf("model metadata alone takes %d bytes, over the %d byte target", len(best), maxBytes)
+			}
+		}
+		data = best
+		chain = top(lo)
+
+		kept := 0
+		for _, suffixes := range chain {
+			kept += suffixTotal(suffixes)
+		}
+		report.Coverage = float64(kept) / float64(total)
+	} else {
+		report.Coverage = 1
+	}
+
+	report.Bytes = len(data)
+	report.Prefixes = len(chain)
+	if report.Bytes > EmbedSizeLimit {
+		report.Warning = fmt.Sprintf("saved model is %d MiB, over the %d MiB that embeds comfortably; pass a byte target to prune it",
+			report.Bytes>>20, EmbedSizeLimit>>20)
+	}
+	return data, report, nil
+}
+
+// prefixesByFrequency lists the prefixes of chain most occurrences first,
+// along with the total number of occurrences
+func prefixesByFrequency(chain map[string]map[string]int) ([]string, int) {
+	prefixes := make([]string, 0, len(chain))
+	counts := make(map[string]int, len(chain))
+	total := 0
+	for prefix, suffixes := range chain {
+		prefixes = append(prefixes, prefix)
+		counts[prefix] = suffixTotal(suffixes)
+		total += counts[prefix]
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		a, b := counts[prefixes[i]], counts[prefixes[j]]
+		if a != b {
+			return a > b
+		}
+		return prefixes[i] < prefixes[j]
+	})
+	return prefixes, total
+}
@@ -0,0 +1,69 @@
+package gophertext
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// encryptedMagic prefixes encrypted model files
+const encryptedMagic = "GTENC1"
+
+// ErrDecryptionFailed is returned when an encrypted model can't be opened
+// with the supplied key
+var ErrDecryptionFailed = errors.New("model decryption failed")
+
+// SaveEncrypted serializes the model and seals it with AES-GCM. The key must
+// be 16, 24 or 32 bytes long (AES-128, AES-192 or AES-256).
+func (m *MarkovModel) SaveEncrypted(key []byte) ([]byte, error) {
+	gcm, err := newModelCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := m.Save()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(encryptedMagic) + len(nonce) + len(data) + gcm.Overhead())
+	buf.WriteString(encryptedMagic)
+	buf.Write(nonce)
+	// The magic header is authenticated so it can't be swapped undetected
+	return gcm.Seal(buf.Bytes(), nonce, data, []byte(encryptedMagic)), nil
+}
+
+// LoadEncrypted decrypts a model written by SaveEncrypted and loads it
+func (m *MarkovModel) LoadEncrypted(data, key []byte) error {
+	gcm, err := newModelCipher(key)
+	if err != nil {
+		return err
+	}
+	header := len(encryptedMagic) + gcm.NonceSize()
+	if len(data) < header+gcm.Overhead() || string(data[:len(encryptedMagic)]) != encryptedMagic {
+		return fmt.Errorf("%w: model is not encrypted", ErrDecryptionFailed)
+	}
+
+	nonce := data[len(encryptedMagic):header]
+	plain, err := gcm.Open(nil, nonce, data[header:], []byte(encryptedMagic))
+	if err != nil {
+		return ErrDecryptionFailed
+	}
+	return m.Load(plain)
+}
+
+func newModelCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
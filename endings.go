@@ -0,0 +1,93 @@
+package gophertext
+
+// endingWindow is how many words before the requested length EndingBias
+// starts to favor sentence ends
+const endingWindow = 8
+
+// noteRemaining records how many words generation still needs, so sample
+// can apply EndingBias near the end
+func (m *MarkovModel) noteRemaining(remaining int, o *generateOptions) {
+	o.ending = 0
+	if m.config.EndingBias > 0 && remaining > 0 && remaining <= endingWindow {
+		// Ramp up to the full bias on the last word
+		o.ending = m.config.EndingBias * float64(endingWindow-remaining+1) / endingWindow
+		o.remaining = remaining
+	}
+}
+
+// trimIncomplete drops the words after the last sentence-terminal word.
+// Output without any complete sentence is kept whole rather than emptied.
+func (m *MarkovModel) trimIncomplete(words []string) []string {
+	for i := len(words) - 1; i >= 0; i-- {
+		if m.splitter.IsTerminal(words[i]) {
+			return words[:i+1]
+		}
+	}
+	return words
+}
+
+// endingWeight is the factor EndingBias applies to word after prefix. It
+// grows with how much likelier than average the word makes the sentence to
+// end exactly on the last requested word, so terminal words are favored
+// only for the last word and earlier words by where they lead.
+func (m *MarkovModel) endingWeight(prefix, word string, o *generateOptions) float64 {
+	idx := m.prefixIndex()
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	if idx.endings == nil {
+		idx.endings = make(map[string][]float64)
+	}
+
+	average := m.endsIn(idx.endings, prefix, o.remaining)
+	if average == 0 {
+		return 1
+	}
+	p := 0.0
+	if m.splitter.IsTerminal(word) {
+		if o.remaining == 1 {
+			p = 1
+		}
+	} else if o.remaining > 1 {
+		words := splitKey(prefix)
+		words = append(words[max(0, len(words)-m.config.Order+1):], word)
+		p = m.endsIn(idx.endings, joinKey(words), o.remaining-1)
+	}
+	return 1 + o.ending*p/average
+}
+
+// endsIn is the probability that generation from prefix first reaches a
+// sentence-terminal word after exactly steps words, memoized in memo.
+// Callers must hold indexMu.
+func (m *MarkovModel) endsIn(memo map[string][]float64, prefix string, steps int) float64 {
+	known := memo[prefix]
+	if known == nil {
+		known = make([]float64, endingWindow+1)
+		for i := range known {
+			known[i] = -1
+		}
+		memo[prefix] = known
+	}
+	if known[steps] >= 0 {
+		return known[steps]
+	}
+
+	suffixes := m.chain[prefix]
+	total := suffixTotal(suffixes)
+	p := 0.0
+	if total > 0 {
+		words := splitKey(prefix)
+		tail := words[min(1, len(words)):len(words):len(words)]
+		for s, n := range suffixes {
+			terminal := m.splitter.IsTerminal(s)
+			switch {
+			case steps == 1 && terminal:
+				p += float64(n)
+			case steps > 1 && !terminal:
+				p += float64(n) * m.endsIn(memo, joinKey(append(tail, s)), steps-1)
+			}
+		}
+		p /= float64(total)
+	}
+	known[steps] = p
+	return p
+}
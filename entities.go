@@ -0,0 +1,143 @@
+package gophertext
+
+import (
+	"math/rand"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Entity placeholder tokens produced by entity mode
+const (
+	EntityPerson = "<PERSON>"
+	EntityPlace  = "<PLACE>"
+)
+
+// EntitySpan marks tokens [Start, End) as a named entity of Kind
+type EntitySpan struct {
+	Start, End int
+	Kind       string // EntityPerson, EntityPlace or a custom "<KIND>" token
+}
+
+// EntityRecognizer finds named entities in raw, case-preserved tokens
+type EntityRecognizer interface {
+	Recognize(tokens []string) []EntitySpan
+}
+
+// HeuristicRecognizer treats runs of capitalized words that don't start a
+// sentence as entities. Runs following a locative preposition become
+// places; everything else is a person.
+type HeuristicRecognizer struct {
+	Splitter *SentenceSplitter // Sentence boundaries (default splitter when nil)
+}
+
+var (
+	placePrepositions = map[string]bool{
+		"in": true, "at": true, "from": true, "to": true, "near": true,
+		"into": true, "towards": true, "toward": true, "across": true,
+	}
+	notEntities = map[string]bool{
+		"i": true, "god": true, "monday": true, "tuesday": true, "wednesday": true,
+		"thursday": true, "friday": true, "saturday": true, "sunday": true,
+		"january": true, "february": true, "march": true, "april": true, "may": true,
+		"june": true, "july": true, "august": true, "september": true,
+		"october": true, "november": true, "december": true,
+	}
+)
+
+// Recognize implements EntityRecognizer
+func (h HeuristicRecognizer) Recognize(tokens []string) []EntitySpan {
+	splitter := h.Splitter
+	if splitter == nil {
+		splitter = NewSentenceSplitter("")
+	}
+
+	var spans []EntitySpan
+	for i := 0; i < len(tokens); i++ {
+		sentenceStart := i == 0 || splitter.IsTerminal(tokens[i-1])
+		if sentenceStart || !isCapitalized(tokens[i]) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(tokens) && isCapitalized(tokens[end]) && !endsClause(tokens[end-1]) {
+			end++
+		}
+
+		kind := EntityPerson
+		if i > 0 && placePrepositions[strings.ToLower(coreWord(tokens[i-1]))] {
+			kind = EntityPlace
+		}
+		spans = append(spans, EntitySpan{Start: i, End: end, Kind: kind})
+		i = end - 1
+	}
+	return spans
+}
+
+func isCapitalized(token string) bool {
+	core := coreWord(token)
+	r, _ := utf8.DecodeRuneInString(core)
+	if !unicode.IsUpper(r) || notEntities[strings.ToLower(core)] {
+		return false
+	}
+	// All-caps words are headings or acronyms rather than names
+	return strings.ToUpper(core) != core || utf8.RuneCountInString(core) == 1
+}
+
+func endsClause(token string) bool {
+	r, _ := utf8.DecodeLastRuneInString(token)
+	return unicode.IsPunct(r)
+}
+
+// coreWord strips leading and trailing punctuation from a token
+func coreWord(token string) string {
+	return strings.TrimFunc(token, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SetEntityRecognizer replaces the recognizer used in entity mode
+func (m *MarkovModel) SetEntityRecognizer(r EntityRecognizer) {
+	m.recognizer = r
+}
+
+// maskEntities tokenizes raw text, replaces entity spans with their kind
+// token and normalizes everything else
+func (m *MarkovModel) maskEntities(text string) []string {
+	raw := joinContractions(strings.Fields(normalizeApostrophes(nfc(text))))
+	recognizer := m.recognizer
+	if recognizer == nil {
+		recognizer = HeuristicRecognizer{Splitter: m.splitter}
+	}
+
+	words := make([]string, 0, len(raw))
+	next := 0
+	for _, span := range recognizer.Recognize(raw) {
+		if span.Start < next || span.End > len(raw) || span.Start >= span.End {
+			continue
+		}
+		for _, w := range raw[next:span.Start] {
+			words = append(words, m.normalizeText(w))
+		}
+
+		first, last := raw[span.Start], raw[span.End-1]
+		lead := first[:strings.Index(first, coreWord(first))]
+		trail := last[strings.LastIndex(last, coreWord(last))+len(coreWord(last)):]
+		words = append(words, m.normalizeText(lead)+span.Kind+m.normalizeText(trail))
+		next = span.End
+	}
+	for _, w := range raw[next:] {
+		words = append(words, m.normalizeText(w))
+	}
+	return words
+}
+
+// substituteEntities replaces an entity token inside word with a name of that kind
+func substituteEntities(word string, names map[string][]string, rng *rand.Rand) string {
+	for kind, list := range names {
+		if len(list) > 0 && strings.Contains(word, kind) {
+			return strings.Replace(word, kind, list[rng.Intn(len(list))], 1)
+		}
+	}
+	return word
+}
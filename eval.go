@@ -0,0 +1,91 @@
+package gophertext
+
+import (
+	"math"
+	"strings"
+)
+
+// SplitCorpus divides text into training and held-out parts at sentence
+// boundaries. ratio is the share of sentences kept for training; held-out
+// sentences are spread evenly through the corpus rather than taken from
+// the end, so both parts cover the same material. The split is
+// deterministic, making evaluations repeatable.
+func SplitCorpus(text string, ratio float64) (train, heldOut string) {
+	if ratio >= 1 {
+		return text, ""
+	}
+	if ratio <= 0 {
+		return "", text
+	}
+
+	var trainParts, heldParts []string
+	acc := 0.0
+	for _, sentence := range NewSentenceSplitter("").Split(text) {
+		acc += 1 - ratio
+		if acc >= 1 {
+			acc--
+			heldParts = append(heldParts, sentence)
+		} else {
+			trainParts = append(trainParts, sentence)
+		}
+	}
+	return strings.Join(trainParts, " "), strings.Join(heldParts, " ")
+}
+
+// EvalMetrics measures how well a model predicts held-out text
+type EvalMetrics struct {
+	Predictions  int     // Words predicted (every word after the first Order)
+	KnownPrefix  int     // Predictions whose prefix was seen in training
+	Covered      int     // Predictions given non-zero probability
+	Coverage     float64 // Covered / Predictions
+	CrossEntropy float64 // Mean bits per covered prediction
+	Perplexity   float64 // 2^CrossEntropy (+Inf when nothing was covered)
+}
+
+// Evaluate scores the model on held-out text. Lower perplexity means the
+// model predicts the text better. Unseen continuations are excluded from the
+// perplexity of an unsmoothed model, so compare Coverage too; smoothed
+// models cover every prediction.
+func (m *MarkovModel) Evaluate(heldOut string) EvalMetrics {
+	words := m.tokenize(heldOut)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var metrics EvalMetrics
+	bits := 0.0
+	order := m.config.Order
+	for i := order; i < len(words); i++ {
+		metrics.Predictions++
+		suffixes := m.chain[joinKey(words[i-order:i])]
+		if len(suffixes) > 0 {
+			metrics.KnownPrefix++
+		}
+
+		if m.config.Smoothing != SmoothingNone {
+			metrics.Covered++
+			bits -= math.Log2(m.smoothedProb(words[i-order:i], words[i]))
+			continue
+		}
+		if len(suffixes) == 0 {
+			continue
+		}
+
+		count := suffixes[words[i]]
+		if count == 0 {
+			continue
+		}
+		metrics.Covered++
+		bits -= math.Log2(float64(count) / float64(suffixTotal(suffixes)))
+	}
+
+	metrics.Perplexity = math.Inf(1)
+	if metrics.Predictions > 0 {
+		metrics.Coverage = float64(metrics.Covered) / float64(metrics.Predictions)
+	}
+	if metrics.Covered > 0 {
+		metrics.CrossEntropy = bits / float64(metrics.Covered)
+		metrics.Perplexity = math.Exp2(metrics.CrossEntropy)
+	}
+	return metrics
+}
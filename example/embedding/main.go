@@ -0,0 +1,36 @@
+// Embedding compiles a trained model into the binary with go:embed and
+// generates from it. Retrain the model with the training example.
+package main
+
+import (
+	"embed"
+	"github.com/jasonlovesdoggo/gophertext"
+	"os"
+)
+
+//go:embed models/literature.gt
+var embeddedModels embed.FS
+
+func main() {
+	model, err := gophertext.LoadEmbedded(embeddedModels, "models/literature.gt")
+	if err != nil {
+		panic(err)
+	}
+
+	if err := model.SetEndingBias(5); err != nil {
+		panic(err)
+	}
+	text, err := model.Generate(100)
+	if err != nil {
+		panic(err)
+	}
+
+	err = gophertext.Render(os.Stdout, "# From the literature model\n\n"+text, gophertext.RenderOptions{
+		Width:                 72,
+		Color:                 os.Getenv("NO_COLOR") == "",
+		SentencesPerParagraph: 5,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
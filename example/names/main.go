@@ -0,0 +1,56 @@
+// Names invents names by training on letters instead of words: every name
+// is spelled out with spaces and ends in a period, so the chain learns
+// which letters follow which.
+package main
+
+import (
+	"fmt"
+	"github.com/jasonlovesdoggo/gophertext"
+	"log"
+	"strings"
+)
+
+var names = []string{
+	"alice", "amelia", "beatrice", "caroline", "charlotte", "eleanor", "elizabeth",
+	"florence", "harriet", "isabella", "josephine", "louisa", "margaret", "matilda",
+	"albert", "arthur", "benjamin", "charles", "edmund", "frederick", "george",
+	"henry", "jonathan", "leonard", "nathaniel", "oliver", "robert", "theodore",
+}
+
+func main() {
+	var corpus strings.Builder
+	for _, name := range names {
+		corpus.WriteString(strings.Join(strings.Split(name, ""), " "))
+		corpus.WriteString(" . ")
+	}
+
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{
+		Order:     2,
+		MaxRepeat: 2,
+	})
+	if err := model.BuildModel(corpus.String()); err != nil {
+		log.Fatal(err)
+	}
+
+	letters, err := model.Generate(300)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	known := make(map[string]bool)
+	for _, name := range names {
+		known[name] = true
+	}
+	printed := 0
+	for _, name := range strings.Split(strings.ReplaceAll(letters, " ", ""), ".") {
+		name = strings.ToLower(name)
+		if len(name) < 4 || len(name) > 10 || known[name] {
+			continue
+		}
+		known[name] = true
+		fmt.Println(strings.ToUpper(name[:1]) + name[1:])
+		if printed++; printed == 10 {
+			break
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Prompts continues each command-line argument with generated text:
+//
+//	go run ./prompts "the river" "in the morning"
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/jasonlovesdoggo/gophertext"
+	"log"
+	"os"
+)
+
+func main() {
+	path := flag.String("model", "embedding/models/literature.gt", "model file to load")
+	words := flag.Int("words", 30, "words to add to each prompt")
+	flag.Parse()
+
+	data, err := os.ReadFile(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{})
+	if err := model.Load(data); err != nil {
+		log.Fatal(err)
+	}
+
+	prompts := flag.Args()
+	if len(prompts) == 0 {
+		prompts = []string{"the old house", "it was"}
+	}
+	for _, prompt := range prompts {
+		text, err := model.GenerateFrom(prompt, *words, gophertext.WithFallback(gophertext.FallbackBackoff))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s\n\n", text)
+	}
+}
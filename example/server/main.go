@@ -0,0 +1,58 @@
+// Server serves generated text over HTTP:
+//
+//	go run ./server
+//	curl 'localhost:8080/generate?words=50&prompt=the+river'
+package main
+
+import (
+	"flag"
+	"github.com/jasonlovesdoggo/gophertext"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+func main() {
+	path := flag.String("model", "embedding/models/literature.gt", "model file to load")
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	flag.Parse()
+
+	data, err := os.ReadFile(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{})
+	if err := model.Load(data); err != nil {
+		log.Fatal(err)
+	}
+
+	http.HandleFunc("/generate", func(w http.ResponseWriter, r *http.Request) {
+		words := 100
+		if s := r.URL.Query().Get("words"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 || n > 10000 {
+				http.Error(w, "words must be between 1 and 10000", http.StatusBadRequest)
+				return
+			}
+			words = n
+		}
+
+		var text string
+		var err error
+		if prompt := r.URL.Query().Get("prompt"); prompt != "" {
+			text, err = model.GenerateFrom(prompt, words)
+		} else {
+			text, err = model.Generate(words)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(text + "\n"))
+	})
+
+	log.Printf("serving %s (model %.12s) on http://%s/generate", *path, model.Fingerprint(), *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
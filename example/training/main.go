@@ -0,0 +1,45 @@
+// Training builds a model from a text corpus and saves it. Run it from the
+// example directory to refresh the model used by the other examples:
+//
+//	go run ./training
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/jasonlovesdoggo/gophertext"
+	"log"
+)
+
+func main() {
+	corpus := flag.String("corpus", "corpus/literature.txt", "text file to train on")
+	out := flag.String("out", "embedding/models/literature.gt", "where to save the model")
+	order := flag.Int("order", 3, "words of context per prediction")
+	flag.Parse()
+
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{
+		Order:          *order,
+		MaxRepeat:      2,
+		MinSentenceLen: 5,
+		MaxSentenceLen: 25,
+		ParagraphBreak: 5,
+		Deterministic:  true,
+	})
+
+	text, err := gophertext.LoadHugeTextCorpus(*corpus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := model.BuildModel(text); err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := model.Save()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := gophertext.SaveModelToFile(data, *out); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Model trained and saved to %s (fingerprint %.12s)\n", *out, model.Fingerprint())
+}
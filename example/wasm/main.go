@@ -0,0 +1,78 @@
+//go:build js && wasm
+
+// Command wasm exposes GopherText to JavaScript. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o gophertext.wasm ./example/wasm
+//
+// and serve it next to index.html and Go's wasm_exec.js.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/jasonlovesdoggo/gophertext"
+)
+
+var model *gophertext.MarkovModel
+
+func main() {
+	js.Global().Set("gophertext", js.ValueOf(map[string]interface{}{
+		"load":     js.FuncOf(load),
+		"train":    js.FuncOf(train),
+		"generate": js.FuncOf(generate),
+	}))
+	select {}
+}
+
+// load(bytes: Uint8Array) replaces the model with a saved one
+func load(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 {
+		return errorResult("load expects a Uint8Array")
+	}
+	data := make([]byte, args[0].Get("length").Int())
+	js.CopyBytesToGo(data, args[0])
+
+	m := gophertext.NewMarkovModel(gophertext.MarkovConfig{})
+	if err := m.Load(data); err != nil {
+		return errorResult(err.Error())
+	}
+	model = m
+	return js.Null()
+}
+
+// train(text: string, order?: number) trains a fresh model in the browser
+func train(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("train expects the corpus text")
+	}
+	order := 2
+	if len(args) > 1 {
+		order = args[1].Int()
+	}
+	m := gophertext.NewMarkovModel(gophertext.MarkovConfig{Order: order})
+	if err := m.BuildModel(args[0].String()); err != nil {
+		return errorResult(err.Error())
+	}
+	model = m
+	return js.Null()
+}
+
+// generate(words: number) returns generated text
+func generate(this js.Value, args []js.Value) interface{} {
+	if model == nil {
+		return errorResult("no model loaded")
+	}
+	words := 50
+	if len(args) > 0 {
+		words = args[0].Int()
+	}
+	text, err := model.Generate(words)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	return text
+}
+
+func errorResult(msg string) interface{} {
+	return js.Global().Get("Error").New(msg)
+}
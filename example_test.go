@@ -0,0 +1,103 @@
+package gophertext_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jasonlovesdoggo/gophertext"
+)
+
+// Every two-word prefix of this corpus has a single continuation, so
+// continuing a prompt from it gives the same text on every run
+const pangram = "The quick brown fox jumps over the lazy dog near the river bank."
+
+// A seeded model generates the same text on every run
+const exampleCorpus = `The cat sat on the mat. The dog sat on the rug. The cat saw the dog
+and the dog saw the cat. A bird sat on the fence and sang to the cat.
+The dog ran to the fence. The bird flew from the fence to the old tree.`
+
+func ExampleMarkovModel_Generate() {
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{Order: 1, Seed: 42})
+	if err := model.BuildModel(exampleCorpus); err != nil {
+		log.Fatal(err)
+	}
+
+	text, err := model.Generate(12)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(text)
+	// Output:
+	// dog saw the bird sat on the fence. The old tree. The
+}
+
+func ExampleMarkovModel_GenerateFrom() {
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{Order: 2})
+	if err := model.BuildModel(pangram); err != nil {
+		log.Fatal(err)
+	}
+
+	text, err := model.GenerateFrom("the quick", 6)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(text)
+	// Output:
+	// the quick brown fox jumps over the lazy
+}
+
+func ExampleMarkovModel_Load() {
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{Order: 2})
+	if err := model.BuildModel(pangram); err != nil {
+		log.Fatal(err)
+	}
+	data, err := model.Save()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	loaded := gophertext.NewMarkovModel(gophertext.MarkovConfig{})
+	if err := loaded.Load(data); err != nil {
+		log.Fatal(err)
+	}
+	text, err := loaded.GenerateFrom("over the", 4)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(text)
+	// Output:
+	// over the lazy dog near the
+}
+
+// Training on letters instead of words makes the chain invent names
+func Example_names() {
+	var corpus strings.Builder
+	for _, name := range []string{"alice", "amelia", "beatrice", "caroline", "charlotte", "eleanor",
+		"harriet", "isabella", "louisa", "margaret", "matilda", "oliver", "robert", "theodore"} {
+		corpus.WriteString(strings.Join(strings.Split(name, ""), " "))
+		corpus.WriteString(" . ")
+	}
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{Order: 2, MaxRepeat: 2, Seed: 11})
+	if err := model.BuildModel(corpus.String()); err != nil {
+		log.Fatal(err)
+	}
+
+	letters, err := model.Generate(60)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range strings.Split(strings.ReplaceAll(letters, " ", ""), ".") {
+		if name = strings.ToLower(name); len(name) >= 4 {
+			fmt.Println(strings.ToUpper(name[:1]) + name[1:])
+		}
+	}
+	// Output:
+	// Charriet
+	// Isabella
+	// Louisa
+	// Louisabert
+	// Olivert
+	// Mare
+	// Charlo
+}
@@ -0,0 +1,75 @@
+package gophertext
+
+import "strings"
+
+// exclusions holds the normalized words and phrases one generation must avoid
+type exclusions struct {
+	words   map[string]bool // Words that may never be emitted
+	phrases [][]string      // Word sequences that may never appear in order
+}
+
+// WithExcludeWords keeps words out of one generation, without modifying the
+// model. Words are normalized like training text, and generation backtracks
+// around them like a ConstraintSet.
+func WithExcludeWords(words []string) GenerateOption {
+	return func(o *generateOptions) {
+		o.excludeWords = append(o.excludeWords, words...)
+	}
+}
+
+// WithExcludePrefixes keeps phrases out of one generation: the output never
+// starts with or passes through any of them, though their words may still
+// appear elsewhere. Phrases are normalized like training text.
+func WithExcludePrefixes(prefixes []string) GenerateOption {
+	return func(o *generateOptions) {
+		o.excludePrefixes = append(o.excludePrefixes, prefixes...)
+	}
+}
+
+// normalizeExclusions resolves the excluded words and phrases of o against
+// the model's normalization. Callers hold m.mu.
+func (m *MarkovModel) normalizeExclusions(o *generateOptions) {
+	if len(o.excludeWords) == 0 && len(o.excludePrefixes) == 0 {
+		return
+	}
+	ex := &exclusions{words: make(map[string]bool)}
+	for _, w := range o.excludeWords {
+		for _, token := range strings.Fields(m.normalizeText(w)) {
+			ex.words[token] = true
+		}
+	}
+	for _, p := range o.excludePrefixes {
+		if phrase := strings.Fields(m.normalizeText(p)); len(phrase) > 0 {
+			ex.phrases = append(ex.phrases, phrase)
+		}
+	}
+	o.exclusions = ex
+}
+
+// allows reports whether word may follow tokens
+func (ex *exclusions) allows(tokens []string, word string) bool {
+	if ex == nil {
+		return true
+	}
+	if ex.words[word] {
+		return false
+	}
+	for _, phrase := range ex.phrases {
+		n := len(phrase) - 1
+		if n > len(tokens) || phrase[n] != word {
+			continue
+		}
+		tail := tokens[len(tokens)-n:]
+		matched := true
+		for i, w := range phrase[:n] {
+			if tail[i] != w {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package gophertext
+
+// StepExplanation maps one generated word back to the training transition
+// that produced it
+type StepExplanation struct {
+	Prefix       string  // Preceding words the chain looked up
+	Word         string  // Word as normalized for the chain
+	Count        int     // Times Word followed Prefix in training
+	Total        int     // Transitions out of Prefix in training
+	Alternatives int     // Distinct words that followed Prefix
+	Probability  float64 // Count / Total, the chance Word was sampled
+	Fallback     bool    // No training transition explains the word
+}
+
+// Explain walks generated text through the chain and reports, for every word
+// after the first Order, which transition produced it and how often it was
+// seen in training. Text is normalized like training text, so output of
+// Generate or GenerateDebug maps back directly. Steps marked Fallback are
+// where generation jumped after a dead end or a generation rule replaced
+// the word; the trace from GenerateDebug says which.
+func (m *MarkovModel) Explain(generated string) []StepExplanation {
+	words := m.tokenize(generated)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	order := m.config.Order
+	var steps []StepExplanation
+	for i := order; i < len(words); i++ {
+		prefix := joinKey(words[i-order : i])
+		suffixes := m.chain[prefix]
+		step := StepExplanation{
+			Prefix:       prefix,
+			Word:         words[i],
+			Count:        suffixes[words[i]],
+			Total:        suffixTotal(suffixes),
+			Alternatives: len(suffixes),
+		}
+		if step.Count > 0 {
+			step.Probability = float64(step.Count) / float64(step.Total)
+		} else {
+			step.Fallback = true
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
@@ -0,0 +1,219 @@
+package gophertext
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// BulkConfig controls the layout of Elasticsearch/OpenSearch bulk exports
+type BulkConfig struct {
+	Index      string                 // Target index (required)
+	Action     string                 // Bulk action, "index" or "create" (default "index")
+	IDPrefix   string                 // Prefix for sequential document IDs ("" lets the cluster assign IDs)
+	TitleField string                 // Field holding a generated title ("" omits titles)
+	BodyField  string                 // Field holding the generated body (default "body")
+	TitleWords int                    // Words per title (default 6)
+	BodyWords  int                    // Words per body (default 100)
+	Fields     map[string]interface{} // Static fields copied into every document
+}
+
+// ExportBulk writes docs generated documents to w as bulk-index NDJSON,
+// ready to be POSTed to the _bulk endpoint
+func (m *MarkovModel) ExportBulk(w io.Writer, docs int, cfg BulkConfig) error {
+	if cfg.Index == "" {
+		return fmt.Errorf("bulk export requires an index name")
+	}
+	if cfg.Action == "" {
+		cfg.Action = "index"
+	}
+	if cfg.Action != "index" && cfg.Action != "create" {
+		return fmt.Errorf("unsupported bulk action %q", cfg.Action)
+	}
+	if cfg.BodyField == "" {
+		cfg.BodyField = "body"
+	}
+	if cfg.TitleWords <= 0 {
+		cfg.TitleWords = 6
+	}
+	if cfg.BodyWords <= 0 {
+		cfg.BodyWords = 100
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+
+	for i := 1; i <= docs; i++ {
+		meta := map[string]string{"_index": cfg.Index}
+		if cfg.IDPrefix != "" {
+			meta["_id"] = fmt.Sprintf("%s%d", cfg.IDPrefix, i)
+		}
+		if err := enc.Encode(map[string]interface{}{cfg.Action: meta}); err != nil {
+			return err
+		}
+
+		doc := make(map[string]interface{}, len(cfg.Fields)+2)
+		for k, v := range cfg.Fields {
+			doc[k] = v
+		}
+		if cfg.TitleField != "" {
+			title, err := m.generateTitle(cfg.TitleWords)
+			if err != nil {
+				return err
+			}
+			doc[cfg.TitleField] = title
+		}
+		body, err := m.Generate(cfg.BodyWords)
+		if err != nil {
+			return err
+		}
+		doc[cfg.BodyField] = body
+
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ColumnKind selects what kind of text a seed column receives
+type ColumnKind int
+
+const (
+	ColumnTitle ColumnKind = iota // Short capitalized line without punctuation
+	ColumnBody                    // Free-form generated text
+	ColumnSlug                    // URL-safe slug derived from a generated title
+)
+
+// ColumnSpec describes one column of an SQL seed export
+type ColumnSpec struct {
+	Name  string     // Column name (letters, digits, underscores and dots only)
+	Kind  ColumnKind // Kind of generated text
+	Words int        // Words to generate (defaults: title 6, body 50, slug 4)
+}
+
+// ExportSQLSeed writes rows INSERT statements for table to w, filling each
+// column with generated text according to its spec
+func (m *MarkovModel) ExportSQLSeed(w io.Writer, table string, columns []ColumnSpec, rows int) error {
+	if !validIdentifier(table) {
+		return fmt.Errorf("invalid table name %q", table)
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("sql seed export requires at least one column")
+	}
+
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		if !validIdentifier(col.Name) {
+			return fmt.Errorf("invalid column name %q", col.Name)
+		}
+		names[i] = col.Name
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES (", table, strings.Join(names, ", "))
+
+	bw := bufio.NewWriter(w)
+	values := make([]string, len(columns))
+	for i := 0; i < rows; i++ {
+		for j, col := range columns {
+			text, err := m.columnText(col)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", col.Name, err)
+			}
+			values[j] = quoteSQL(text)
+		}
+		bw.WriteString(prefix)
+		bw.WriteString(strings.Join(values, ", "))
+		bw.WriteString(");\n")
+	}
+
+	return bw.Flush()
+}
+
+func (m *MarkovModel) columnText(col ColumnSpec) (string, error) {
+	switch col.Kind {
+	case ColumnTitle:
+		return m.generateTitle(wordsOr(col.Words, 6))
+	case ColumnBody:
+		return m.Generate(wordsOr(col.Words, 50))
+	case ColumnSlug:
+		title, err := m.generateTitle(wordsOr(col.Words, 4))
+		if err != nil {
+			return "", err
+		}
+		return slugify(title), nil
+	default:
+		return "", fmt.Errorf("unknown column kind %d", col.Kind)
+	}
+}
+
+func wordsOr(words, fallback int) int {
+	if words > 0 {
+		return words
+	}
+	return fallback
+}
+
+// slugify lowercases text and joins its alphanumeric runs with dashes
+func slugify(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+func quoteSQL(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func validIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case i > 0 && (r == '.' || (r >= '0' && r <= '9')):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// generateTitle produces a short capitalized line without sentence punctuation
+func (m *MarkovModel) generateTitle(words int) (string, error) {
+	text, err := m.Generate(words)
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(text)
+	for i, f := range fields {
+		fields[i] = strings.TrimFunc(f, func(r rune) bool {
+			return unicode.IsPunct(r) && r != '\'' && r != '-'
+		})
+	}
+	title := strings.Join(strings.Fields(strings.Join(fields, " ")), " ")
+	if title == "" {
+		return "", fmt.Errorf("generated title is empty")
+	}
+
+	r := []rune(title)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r), nil
+}
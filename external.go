@@ -0,0 +1,205 @@
+package gophertext
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExternalConfig controls disk-spilling training
+type ExternalConfig struct {
+	TempDir     string // Parent directory for segment files (default os.TempDir())
+	SegmentSize int    // Transitions buffered in memory before spilling (default 1<<20)
+}
+
+type transition struct {
+	prefix, suffix string
+}
+
+// TrainExternal trains a model from a corpus stream using an external sort.
+// Transitions are buffered up to SegmentSize, spilled to sorted segment
+// files on disk and finally merged into the model, so neither the corpus
+// nor the unmerged transitions ever need to fit in memory.
+func TrainExternal(r io.Reader, cfg MarkovConfig, ext ExternalConfig) (*MarkovModel, error) {
+	if ext.SegmentSize <= 0 {
+		ext.SegmentSize = 1 << 20
+	}
+	dir, err := os.MkdirTemp(ext.TempDir, "gophertext-segments-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create segment directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	model := NewMarkovModel(cfg)
+	if model.config.Order == OrderAuto {
+		model.config.Order = defaultOrder
+	}
+	order := model.config.Order
+
+	var segments []string
+	buffer := make([]transition, 0, ext.SegmentSize)
+	spill := func() error {
+		if len(buffer) == 0 {
+			return nil
+		}
+		name := filepath.Join(dir, fmt.Sprintf("segment-%05d", len(segments)))
+		if err := writeSegment(name, buffer); err != nil {
+			return err
+		}
+		segments = append(segments, name)
+		buffer = buffer[:0]
+		return nil
+	}
+
+	window := make([]string, 0, order+1)
+	br := bufio.NewReaderSize(r, 1<<20)
+	for {
+		line, readErr := br.ReadString('\n')
+		for _, w := range model.trainingTokens(line) {
+			window = append(window, w)
+			if len(window) <= order {
+				continue
+			}
+			buffer = append(buffer, transition{
+				prefix: joinKey(window[:order]),
+				suffix: window[order],
+			})
+			window = append(window[:0], window[1:]...)
+
+			if len(buffer) == ext.SegmentSize {
+				if err := spill(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("error reading corpus: %w", readErr)
+		}
+	}
+	if err := spill(); err != nil {
+		return nil, err
+	}
+
+	if err := mergeSegments(segments, model.chain); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// writeSegment sorts transitions and writes them as run-length encoded
+// "prefix\tsuffix\tcount" lines. The prefix and suffix are Go-quoted, so
+// tokens may contain tabs or newlines.
+func writeSegment(name string, buffer []transition) error {
+	sort.Slice(buffer, func(i, j int) bool {
+		if buffer[i].prefix != buffer[j].prefix {
+			return buffer[i].prefix < buffer[j].prefix
+		}
+		return buffer[i].suffix < buffer[j].suffix
+	})
+
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create segment: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for i := 0; i < len(buffer); {
+		j := i + 1
+		for j < len(buffer) && buffer[j] == buffer[i] {
+			j++
+		}
+		fmt.Fprintf(w, "%q\t%q\t%d\n", buffer[i].prefix, buffer[i].suffix, j-i)
+		i = j
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write segment: %w", err)
+	}
+	return f.Close()
+}
+
+type segmentEntry struct {
+	transition
+	count  int
+	source int
+}
+
+type segmentHeap []segmentEntry
+
+func (h segmentHeap) Len() int { return len(h) }
+func (h segmentHeap) Less(i, j int) bool {
+	if h[i].prefix != h[j].prefix {
+		return h[i].prefix < h[j].prefix
+	}
+	return h[i].suffix < h[j].suffix
+}
+func (h segmentHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *segmentHeap) Push(x interface{}) { *h = append(*h, x.(segmentEntry)) }
+func (h *segmentHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// mergeSegments k-way merges sorted segment files into chain
+func mergeSegments(segments []string, chain map[string]map[string]int) error {
+	scanners := make([]*bufio.Scanner, len(segments))
+	for i, name := range segments {
+		f, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("failed to open segment: %w", err)
+		}
+		defer f.Close()
+		scanners[i] = bufio.NewScanner(f)
+		scanners[i].Buffer(make([]byte, 64*1024), 16*1024*1024)
+	}
+
+	h := &segmentHeap{}
+	advance := func(i int) error {
+		if !scanners[i].Scan() {
+			return scanners[i].Err()
+		}
+		parts := strings.Split(scanners[i].Text(), "\t")
+		if len(parts) != 3 {
+			return fmt.Errorf("corrupt segment line %q", scanners[i].Text())
+		}
+		prefix, err := strconv.Unquote(parts[0])
+		if err != nil {
+			return fmt.Errorf("corrupt segment prefix: %w", err)
+		}
+		suffix, err := strconv.Unquote(parts[1])
+		if err != nil {
+			return fmt.Errorf("corrupt segment suffix: %w", err)
+		}
+		n, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return fmt.Errorf("corrupt segment count: %w", err)
+		}
+		heap.Push(h, segmentEntry{transition{prefix, suffix}, n, i})
+		return nil
+	}
+	for i := range scanners {
+		if err := advance(i); err != nil {
+			return err
+		}
+	}
+
+	for h.Len() > 0 {
+		e := heap.Pop(h).(segmentEntry)
+		addTransition(chain, e.prefix, e.suffix, e.count)
+		if err := advance(e.source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package gophertext
+
+import "errors"
+
+// ErrDeadEnd is returned by generation with FallbackAbort when the chain
+// has no continuation for the current prefix
+var ErrDeadEnd = errors.New("generation reached a dead end")
+
+// FallbackStrategy selects how generation recovers from a prefix with no
+// continuations
+type FallbackStrategy int
+
+const (
+	FallbackRandomPrefix     FallbackStrategy = iota // Jump to a random prefix (default)
+	FallbackBackoff                                  // Jump to a prefix sharing the longest possible run of trailing words
+	FallbackSentenceRestart                          // Jump to a prefix that starts a sentence
+	FallbackAbort                                    // Stop and return ErrDeadEnd
+	FallbackRecentVocabulary                         // Jump to a prefix containing a word used recently in the output
+)
+
+// recentWindow is how many trailing output words FallbackRecentVocabulary
+// draws restart candidates from
+const recentWindow = 50
+
+// WithFallback sets how a generation recovers from dead ends
+func WithFallback(strategy FallbackStrategy) GenerateOption {
+	return func(o *generateOptions) {
+		o.fallback = strategy
+	}
+}
+
+// restartPrefix picks the prefix generation continues from after buffer
+// dead-ended, along with how many words of context it shares with buffer.
+// recent holds the words emitted so far.
+func (m *MarkovModel) restartPrefix(strategy FallbackStrategy, buffer, recent []string) (string, int) {
+	switch strategy {
+	case FallbackBackoff:
+		idx := m.prefixIndex()
+		for k := shorterContext(len(buffer), m.config.Order); k >= 1; k-- {
+			if prefixes := idx.byEnding[joinKey(buffer[len(buffer)-k:])]; len(prefixes) > 0 {
+				return prefixes[m.rng.Intn(len(prefixes))], k
+			}
+		}
+	case FallbackSentenceRestart:
+		if starts := m.prefixIndex().starts; len(starts) > 0 {
+			return starts[m.rng.Intn(len(starts))], 0
+		}
+	case FallbackRecentVocabulary:
+		if prefix, ok := m.recentPrefix(recent); ok {
+			return prefix, 0
+		}
+	}
+	return m.randomPrefix(), 0
+}
+
+// promptContext picks the prefix a prompt is continued from. The last Order
+// prompt words are used when the chain knows them; otherwise a prefix
+// ending in the longest run of trailing prompt words the chain has seen, so
+// arbitrary prompts still find a continuation point.
+func (m *MarkovModel) promptContext(prompt []string) []string {
+	order := m.config.Order
+	if len(prompt) >= order {
+		tail := prompt[len(prompt)-order:]
+		if len(m.chain[joinKey(tail)]) > 0 {
+			return tail
+		}
+	}
+	idx := m.prefixIndex()
+	for k := min(len(prompt), order-1); k >= 1; k-- {
+		if prefixes := idx.byEnding[joinKey(prompt[len(prompt)-k:])]; len(prefixes) > 0 {
+			return splitKey(prefixes[m.rng.Intn(len(prefixes))])
+		}
+	}
+	return prompt[max(0, len(prompt)-order):]
+}
+
+// recentPrefix picks a prefix containing one of the last recentWindow
+// words. Words are weighted by how often they were used and inversely by
+// how many prefixes contain them, so distinctive topic words are preferred
+// over function words like "the".
+func (m *MarkovModel) recentPrefix(recent []string) (string, bool) {
+	if len(recent) > recentWindow {
+		recent = recent[len(recent)-recentWindow:]
+	}
+	idx := m.prefixIndex()
+
+	weights := make([]float64, len(recent))
+	total := 0.0
+	for i, w := range recent {
+		if n := len(idx.byWord[w]); n > 0 {
+			weights[i] = 1 / float64(n)
+			total += weights[i]
+		}
+	}
+	if total == 0 {
+		return "", false
+	}
+
+	r := m.rng.Float64() * total
+	for i, weight := range weights {
+		if r < weight {
+			prefixes := idx.byWord[recent[i]]
+			return prefixes[m.rng.Intn(len(prefixes))], true
+		}
+		r -= weight
+	}
+	return "", false
+}
+
+// shorterContext is the longest context shorter than order that fits in a
+// buffer of n words
+func shorterContext(n, order int) int {
+	if n >= order {
+		return order - 1
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+package gophertext
+
+import "strings"
+
+// Mask tokens substituted for personal data by the PII filters
+const (
+	MaskEmail = "<EMAIL>"
+	MaskPhone = "<PHONE>"
+	MaskSSN   = "<SSN>"
+	MaskCard  = "<CARD>"
+)
+
+// CorpusFilter transforms raw corpus text before training
+type CorpusFilter func(text string) string
+
+// PIIFilters masks every supported kind of personal data. Card numbers and
+// SSNs run before phone numbers so their digits aren't claimed as phones.
+var PIIFilters = []CorpusFilter{MaskEmails, MaskCardNumbers, MaskSSNs, MaskPhoneNumbers}
+
+// ApplyFilters runs each filter over text in order
+func ApplyFilters(text string, filters ...CorpusFilter) string {
+	for _, f := range filters {
+		text = f(text)
+	}
+	return text
+}
+
+// ScrubPII masks emails, card numbers, SSN-style IDs and phone numbers
+func ScrubPII(text string) string {
+	return ApplyFilters(text, PIIFilters...)
+}
+
+// MaskEmails replaces email addresses with MaskEmail
+func MaskEmails(text string) string {
+	return emailPattern.ReplaceAllString(text, MaskEmail)
+}
+
+// MaskPhoneNumbers replaces phone numbers of seven or more digits with MaskPhone
+func MaskPhoneNumbers(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range phonePattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		// Skip matches carved out of longer digit runs such as order numbers
+		if (start > 0 && isDigit(text[start-1])) || (end < len(text) && isDigit(text[end])) {
+			continue
+		}
+		if countDigits(text[start:end]) < 7 {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(MaskPhone)
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// MaskSSNs replaces 123-45-6789 style identifiers with MaskSSN
+func MaskSSNs(text string) string {
+	return ssnPattern.ReplaceAllString(text, MaskSSN)
+}
+
+// MaskCardNumbers replaces Luhn-valid 13-19 digit numbers with MaskCard
+func MaskCardNumbers(text string) string {
+	return cardPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if !luhnValid(match) {
+			return match
+		}
+		return MaskCard
+	})
+}
@@ -0,0 +1,22 @@
+package gophertext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Fingerprint returns a SHA-256 hex digest of the chain's transitions and
+// their counts. It hashes the canonical encoding Save writes, so it does not
+// depend on map iteration or training order: two models with the same
+// transitions share a fingerprint, and any count change alters it.
+// Configuration and metadata are not included.
+func (m *MarkovModel) Fingerprint() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var buf bytes.Buffer
+	encodeChain(&buf, m.chain)
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
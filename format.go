@@ -0,0 +1,516 @@
+package gophertext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Binary model format. Save writes it; Load also accepts the older gob
+// encoding.
+//
+//	magic    "GTMODEL"
+//	version  uvarint
+//	flags    uvarint
+//	metadata uvarint length + gob-encoded metaRecord
+//	chain    vocabulary, then prefixes (see encodeChain)
+const (
+	formatMagic   = "GTMODEL"
+	formatVersion = 3
+
+	segmentPrefixes = 1 << 14 // Prefixes per independently decodable segment
+
+	flagFrequencySorted = 1 << 0 // Prefixes are stored most frequent first
+)
+
+var errTruncated = errors.New("model data is truncated")
+
+// modelMeta is everything Save persists besides the chain
+type modelMeta struct {
+	Config   MarkovConfig
+	Updated  time.Time
+	Negative map[string]map[string]int
+	Ngrams   map[uint64]bool
+	Vocab    map[string]bool
+	Casing   map[string]caseCount
+}
+
+// metaRecord is modelMeta as encoded. gob writes maps in random order, so
+// they are flattened into sorted slices to keep Save byte-for-byte
+// reproducible. The map fields are only read, from files that predate this.
+type metaRecord struct {
+	Config   MarkovConfig
+	Updated  time.Time
+	Negative map[string]map[string]int
+	Ngrams   map[uint64]bool
+	Vocab    map[string]bool
+
+	SortedConstraints []namedConstraint
+	SortedNegative    []negativeCount
+	SortedNgrams      []uint64
+	SortedVocab       []string
+	SortedCasing      []casingCount
+}
+
+// namedConstraint is one entry of MarkovConfig.Constraints
+type namedConstraint struct {
+	Name string
+	Set  ConstraintSet
+}
+
+// negativeCount is one negatively trained transition
+type negativeCount struct {
+	Prefix, Word string
+	Count        int
+}
+
+// record flattens meta into its canonical encoded form
+func (meta modelMeta) record() metaRecord {
+	rec := metaRecord{Config: meta.Config, Updated: meta.Updated}
+	rec.Config.Constraints = nil
+	for name, set := range meta.Config.Constraints {
+		rec.SortedConstraints = append(rec.SortedConstraints, namedConstraint{name, set})
+	}
+	sort.Slice(rec.SortedConstraints, func(i, j int) bool {
+		return rec.SortedConstraints[i].Name < rec.SortedConstraints[j].Name
+	})
+	for prefix, words := range meta.Negative {
+		for w, n := range words {
+			rec.SortedNegative = append(rec.SortedNegative, negativeCount{prefix, w, n})
+		}
+	}
+	sort.Slice(rec.SortedNegative, func(i, j int) bool {
+		a, b := rec.SortedNegative[i], rec.SortedNegative[j]
+		if a.Prefix != b.Prefix {
+			return a.Prefix < b.Prefix
+		}
+		return a.Word < b.Word
+	})
+	for h := range meta.Ngrams {
+		rec.SortedNgrams = append(rec.SortedNgrams, h)
+	}
+	sort.Slice(rec.SortedNgrams, func(i, j int) bool { return rec.SortedNgrams[i] < rec.SortedNgrams[j] })
+	for w := range meta.Vocab {
+		rec.SortedVocab = append(rec.SortedVocab, w)
+	}
+	sort.Strings(rec.SortedVocab)
+	for w, c := range meta.Casing {
+		rec.SortedCasing = append(rec.SortedCasing, casingCount{w, c.Capitalized, c.Upper, c.Total})
+	}
+	sort.Slice(rec.SortedCasing, func(i, j int) bool { return rec.SortedCasing[i].Word < rec.SortedCasing[j].Word })
+	return rec
+}
+
+// meta rebuilds the maps flattened by record
+func (rec metaRecord) meta() modelMeta {
+	meta := modelMeta{
+		Config:   rec.Config,
+		Updated:  rec.Updated,
+		Negative: rec.Negative,
+		Ngrams:   rec.Ngrams,
+		Vocab:    rec.Vocab,
+	}
+	if len(rec.SortedConstraints) > 0 {
+		meta.Config.Constraints = make(map[string]ConstraintSet, len(rec.SortedConstraints))
+		for _, c := range rec.SortedConstraints {
+			meta.Config.Constraints[c.Name] = c.Set
+		}
+	}
+	for _, c := range rec.SortedNegative {
+		if meta.Negative == nil {
+			meta.Negative = make(map[string]map[string]int)
+		}
+		if meta.Negative[c.Prefix] == nil {
+			meta.Negative[c.Prefix] = make(map[string]int)
+		}
+		meta.Negative[c.Prefix][c.Word] = c.Count
+	}
+	if len(rec.SortedNgrams) > 0 {
+		meta.Ngrams = make(map[uint64]bool, len(rec.SortedNgrams))
+		for _, h := range rec.SortedNgrams {
+			meta.Ngrams[h] = true
+		}
+	}
+	if len(rec.SortedVocab) > 0 {
+		meta.Vocab = make(map[string]bool, len(rec.SortedVocab))
+		for _, w := range rec.SortedVocab {
+			meta.Vocab[w] = true
+		}
+	}
+	if len(rec.SortedCasing) > 0 {
+		meta.Casing = make(map[string]caseCount, len(rec.SortedCasing))
+		for _, c := range rec.SortedCasing {
+			meta.Casing[c.Word] = caseCount{c.Capitalized, c.Upper, c.Total}
+		}
+	}
+	return meta
+}
+
+// encodeModel writes the binary format. The output depends only on the
+// model's contents, never on map iteration or training order, so the same
+// corpus and config always save to the same bytes.
+func encodeModel(meta modelMeta, chain map[string]map[string]int) ([]byte, error) {
+	var metaBuf bytes.Buffer
+	if err := gob.NewEncoder(&metaBuf).Encode(meta.record()); err != nil {
+		return nil, fmt.Errorf("failed to encode model metadata: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(formatMagic)
+	putUvarint(&buf, formatVersion)
+	putUvarint(&buf, flagFrequencySorted)
+	putUvarint(&buf, uint64(metaBuf.Len()))
+	buf.Write(metaBuf.Bytes())
+	encodeChain(&buf, chain)
+	return buf.Bytes(), nil
+}
+
+// encodeChain writes the chain as a word table followed by segments of
+// prefixes:
+//
+//	words         count, count lengths, then all word bytes back to back
+//	segments      count, then per segment: byte length, prefix count,
+//	              total key bytes, distinct transitions
+//	segment data  per prefix: Order word IDs, distinct suffix count,
+//	              (word ID, count) pairs
+//
+// Prefixes are ordered by total count, most frequent first, so a partial
+// load can stop early. Segments are independent, so the decoder can read
+// them in parallel, and their totals let it presize its buffers.
+// Version 1 files hold a single segment with no byte length, and versions
+// 1 and 2 give total suffix occurrences instead of distinct transitions.
+func encodeChain(buf *bytes.Buffer, chain map[string]map[string]int) {
+	prefixes := make([]string, 0, len(chain))
+	totals := make(map[string]int, len(chain))
+	for prefix, suffixes := range chain {
+		prefixes = append(prefixes, prefix)
+		totals[prefix] = suffixTotal(suffixes)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		a, b := totals[prefixes[i]], totals[prefixes[j]]
+		if a != b {
+			return a > b
+		}
+		return prefixes[i] < prefixes[j]
+	})
+
+	ids := make(map[string]uint64)
+	var words []string
+	id := func(w string) uint64 {
+		n, ok := ids[w]
+		if !ok {
+			n = uint64(len(words))
+			ids[w] = n
+			words = append(words, w)
+		}
+		return n
+	}
+
+	type segment struct {
+		body                            bytes.Buffer
+		prefixes, keyBytes, transitions int
+	}
+	var segments []*segment
+	for i, prefix := range prefixes {
+		if i%segmentPrefixes == 0 {
+			segments = append(segments, &segment{})
+		}
+		seg := segments[len(segments)-1]
+		seg.prefixes++
+		seg.keyBytes += len(prefix)
+		// Store the key's encoded words, which decodeSegment joins back
+		// with spaces
+		for _, w := range strings.Split(prefix, " ") {
+			putUvarint(&seg.body, id(w))
+		}
+		counts := chain[prefix]
+		suffixes := make([]string, 0, len(counts))
+		for w := range counts {
+			suffixes = append(suffixes, w)
+		}
+		sort.Strings(suffixes)
+		putUvarint(&seg.body, uint64(len(suffixes)))
+		for _, w := range suffixes {
+			putUvarint(&seg.body, id(w))
+			putUvarint(&seg.body, uint64(counts[w]))
+		}
+		seg.transitions += len(suffixes)
+	}
+
+	putUvarint(buf, uint64(len(words)))
+	for _, w := range words {
+		putUvarint(buf, uint64(len(w)))
+	}
+	for _, w := range words {
+		buf.WriteString(w)
+	}
+	putUvarint(buf, uint64(len(segments)))
+	for _, seg := range segments {
+		putUvarint(buf, uint64(seg.body.Len()))
+		putUvarint(buf, uint64(seg.prefixes))
+		putUvarint(buf, uint64(seg.keyBytes))
+		putUvarint(buf, uint64(seg.transitions))
+	}
+	for _, seg := range segments {
+		buf.Write(seg.body.Bytes())
+	}
+}
+
+// decodeModel reads the binary format. A non-negative limit stops after
+// that many prefixes; the result reports whether they were the most
+// frequent ones.
+func decodeModel(data []byte, limit int) (modelMeta, map[string]map[string]int, bool, error) {
+	var meta modelMeta
+	var rec metaRecord
+	r := &byteReader{data: data[len(formatMagic):]}
+	version := r.uvarint()
+	if r.err == nil && (version < 1 || version > formatVersion) {
+		return meta, nil, false, fmt.Errorf("unsupported model format version %d", version)
+	}
+	flags := r.uvarint()
+	metaLen := r.uvarint()
+	metaBytes := r.bytes(metaLen)
+	if r.err != nil {
+		return meta, nil, false, r.err
+	}
+	if err := gob.NewDecoder(bytes.NewReader(metaBytes)).Decode(&rec); err != nil {
+		return meta, nil, false, fmt.Errorf("failed to decode model metadata: %w", err)
+	}
+	meta = rec.meta()
+	if meta.Config.Order < 1 && meta.Config.Order != OrderAuto {
+		return meta, nil, false, fmt.Errorf("invalid model: order %d is less than 1", meta.Config.Order)
+	}
+
+	sorted := flags&flagFrequencySorted != 0
+	if !sorted {
+		limit = -1
+	}
+	chain, err := decodeChain(r, meta.Config.Order, version, limit)
+	return meta, chain, sorted, err
+}
+
+// segmentHeader describes one independently decodable run of prefixes
+type segmentHeader struct {
+	data                            []byte
+	prefixes, keyBytes, transitions uint64 // transitions is total occurrences before version 3
+}
+
+// decodedSegment holds a segment's prefixes: entry i has key
+// keys[keyEnd[i-1]:keyEnd[i]] and suffixes[i]
+type decodedSegment struct {
+	keys     string
+	keyEnd   []int
+	suffixes []map[string]int
+}
+
+// decodeChain reads the output of encodeChain. Segments are decoded by
+// parallel workers. Words and prefix keys are carved out of one string per
+// table and segment instead of one per entry; each prefix then gets its own
+// suffix count map, presized from the stored distinct count.
+func decodeChain(r *byteReader, order int, version uint64, limit int) (map[string]map[string]int, error) {
+	wordCount := r.uvarint()
+	if r.err == nil && wordCount > uint64(r.remaining()) {
+		return nil, errTruncated
+	}
+	lengths := make([]int, wordCount)
+	var total uint64
+	longest := 0
+	for i := range lengths {
+		// Every length must fit in the bytes left, which also keeps the
+		// running total from overflowing
+		n := r.uvarint()
+		if r.err != nil {
+			return nil, r.err
+		}
+		if n > uint64(r.remaining()) || total+n > uint64(r.remaining()) {
+			return nil, errTruncated
+		}
+		total += n
+		lengths[i] = int(n)
+		longest = max(longest, lengths[i])
+	}
+	raw := string(r.bytes(total))
+	if r.err != nil {
+		return nil, r.err
+	}
+	words := make([]string, wordCount)
+	for i, n := range lengths {
+		words[i], raw = raw[:n], raw[n:]
+	}
+
+	var headers []segmentHeader
+	if version == 1 {
+		h := segmentHeader{prefixes: r.uvarint(), keyBytes: r.uvarint(), transitions: r.uvarint()}
+		h.data = r.data
+		headers = append(headers, h)
+	} else {
+		count := r.uvarint()
+		if r.err == nil && count > uint64(r.remaining()) {
+			return nil, errTruncated
+		}
+		headers = make([]segmentHeader, count)
+		sizes := make([]uint64, count)
+		for i := range headers {
+			sizes[i] = r.uvarint()
+			headers[i] = segmentHeader{prefixes: r.uvarint(), keyBytes: r.uvarint(), transitions: r.uvarint()}
+		}
+		for i := range headers {
+			headers[i].data = r.bytes(sizes[i])
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if limit >= 0 {
+		headers = limitSegments(headers, uint64(limit))
+	}
+
+	segments := make([]decodedSegment, len(headers))
+	errs := make([]error, len(headers))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	// Headers are all checked before any worker starts, so no worker is
+	// left reading data after an early return
+	prefixTotal := 0
+	for _, h := range headers {
+		if h.prefixes > uint64(len(h.data)) || h.keyBytes > h.prefixes*uint64(order*(longest+1)) {
+			return nil, errTruncated
+		}
+		prefixTotal += int(h.prefixes)
+	}
+	for i, h := range headers {
+		wg.Add(1)
+		go func(i int, h segmentHeader) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			segments[i], errs[i] = decodeSegment(h, words, order)
+		}(i, h)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chain := make(map[string]map[string]int, prefixTotal)
+	for _, seg := range segments {
+		keyStart := 0
+		for i, keyEnd := range seg.keyEnd {
+			chain[seg.keys[keyStart:keyEnd]] = seg.suffixes[i]
+			keyStart = keyEnd
+		}
+	}
+	return chain, nil
+}
+
+// limitSegments trims headers to cover only the first limit prefixes
+func limitSegments(headers []segmentHeader, limit uint64) []segmentHeader {
+	for i := range headers {
+		if h := &headers[i]; h.prefixes >= limit {
+			// Sizes only presize buffers, so an estimate is fine
+			h.keyBytes = h.keyBytes / h.prefixes * limit
+			h.transitions = h.transitions / h.prefixes * limit
+			h.prefixes = limit
+			return headers[:i+1]
+		}
+		limit -= headers[i].prefixes
+	}
+	return headers
+}
+
+func decodeSegment(h segmentHeader, words []string, order int) (decodedSegment, error) {
+	r := &byteReader{data: h.data}
+	seg := decodedSegment{
+		keyEnd:   make([]int, 0, h.prefixes),
+		suffixes: make([]map[string]int, 0, h.prefixes),
+	}
+	keys := make([]byte, 0, h.keyBytes)
+	word := func() string {
+		id := r.uvarint()
+		if id >= uint64(len(words)) {
+			if r.err == nil {
+				r.err = fmt.Errorf("invalid model: word id %d out of range", id)
+			}
+			return ""
+		}
+		return words[id]
+	}
+
+	for i := uint64(0); i < h.prefixes && r.err == nil; i++ {
+		for j := 0; j < order; j++ {
+			if j > 0 {
+				keys = append(keys, ' ')
+			}
+			keys = append(keys, word()...)
+		}
+		distinct := r.uvarint()
+		// Each entry takes at least two bytes, so a corrupt count can't
+		// reserve more than the data could hold
+		counts := make(map[string]int, min(distinct, uint64(r.remaining()/2)))
+		for j := uint64(0); j < distinct && r.err == nil; j++ {
+			w := word()
+			n := r.uvarint()
+			if n > math.MaxInt32 && r.err == nil {
+				r.err = fmt.Errorf("invalid model: count %d out of range", n)
+			}
+			if n > 0 {
+				counts[w] += int(n)
+			}
+		}
+		seg.keyEnd = append(seg.keyEnd, len(keys))
+		seg.suffixes = append(seg.suffixes, counts)
+	}
+	seg.keys = string(keys)
+	return seg, r.err
+}
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	buf.Write(tmp[:binary.PutUvarint(tmp[:], v)])
+}
+
+// byteReader decodes varints from a byte slice, remembering the first error
+type byteReader struct {
+	data []byte
+	err  error
+}
+
+func (r *byteReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errTruncated
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *byteReader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.data)) {
+		r.err = errTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *byteReader) remaining() int {
+	return len(r.data)
+}
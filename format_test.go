@@ -0,0 +1,220 @@
+package gophertext
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// encodeWordTable writes a model file whose word table claims lengths,
+// followed by a few bytes of word data
+func encodeWordTable(t *testing.T, lengths ...uint64) []byte {
+	t.Helper()
+	var metaBuf bytes.Buffer
+	if err := gob.NewEncoder(&metaBuf).Encode(modelMeta{Config: MarkovConfig{Order: 2}}.record()); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(formatMagic)
+	putUvarint(&buf, formatVersion)
+	putUvarint(&buf, 0)
+	putUvarint(&buf, uint64(metaBuf.Len()))
+	buf.Write(metaBuf.Bytes())
+	putUvarint(&buf, uint64(len(lengths)))
+	for _, n := range lengths {
+		putUvarint(&buf, n)
+	}
+	buf.WriteString("abcdefgh")
+	return buf.Bytes()
+}
+
+func TestLoadCorruptWordTable(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		lengths []uint64
+	}{
+		{"negative as int", []uint64{1 << 63}},
+		{"largest", []uint64{math.MaxUint64}},
+		{"overflowing total", []uint64{4, math.MaxUint64 - 2}},
+		{"past the end", []uint64{100}},
+		{"total past the end", []uint64{5, 5}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMarkovModel(MarkovConfig{})
+			if err := m.Load(encodeWordTable(t, tc.lengths...)); !errors.Is(err, errTruncated) {
+				t.Errorf("Load = %v, want %v", err, errTruncated)
+			}
+		})
+	}
+}
+
+// roundTrip saves m, loads the result into a fresh model and checks it
+func roundTrip(t *testing.T, m *MarkovModel) (*MarkovModel, []byte) {
+	t.Helper()
+	data, err := m.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewMarkovModel(MarkovConfig{})
+	if err := loaded.Load(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.ValidateModel(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.config, m.config) {
+		t.Errorf("loaded config %+v, want %+v", loaded.config, m.config)
+	}
+	return loaded, data
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	corpus := testCorpus(t)
+	for _, tc := range []struct {
+		name    string
+		cfg     MarkovConfig
+		private bool // Save adds noise, so the chain differs from the trained one
+	}{
+		{"order 1", MarkovConfig{Order: 1}, false},
+		{"order 2", MarkovConfig{Order: 2}, false},
+		{"order 3", MarkovConfig{Order: 3}, false},
+		{"order 4", MarkovConfig{Order: 4}, false},
+		{"order 5", MarkovConfig{Order: 5}, false},
+		{"order auto", MarkovConfig{Order: OrderAuto}, false},
+		{"vocabulary cap", MarkovConfig{Order: 2, MaxVocabulary: 200}, false},
+		{"casing", MarkovConfig{Order: 2, RestoreCase: 0.8, DetectAcronyms: true}, false},
+		{"privacy threshold", MarkovConfig{Order: 2, PrivacyThreshold: 3}, true},
+		{"privacy noise", MarkovConfig{Order: 2, PrivacyNoise: 0.5, Seed: 1}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMarkovModel(tc.cfg)
+			if err := m.BuildModel(corpus); err != nil {
+				t.Fatal(err)
+			}
+			loaded, data := roundTrip(t, m)
+			if tc.private {
+				if loaded.Fingerprint() == m.Fingerprint() {
+					t.Error("private model saved its exact counts")
+				}
+				again := NewMarkovModel(MarkovConfig{})
+				if err := again.Load(data); err != nil {
+					t.Fatal(err)
+				}
+				if again.Fingerprint() != loaded.Fingerprint() {
+					t.Error("loading the same data twice gave different chains")
+				}
+				return
+			}
+
+			if loaded.Fingerprint() != m.Fingerprint() {
+				t.Error("fingerprint changed across Save and Load")
+			}
+			if !reflect.DeepEqual(loaded.vocab, m.vocab) || !reflect.DeepEqual(loaded.casing, m.casing) {
+				t.Error("vocabulary or casing changed across Save and Load")
+			}
+			resaved, err := loaded.Save()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(resaved, data) {
+				t.Error("saving a loaded model changed its bytes")
+			}
+		})
+	}
+
+	t.Run("untrained order auto", func(t *testing.T) {
+		roundTrip(t, NewMarkovModel(MarkovConfig{Order: OrderAuto}))
+	})
+}
+
+func TestLoadLegacyGob(t *testing.T) {
+	m := NewMarkovModel(MarkovConfig{Order: 2})
+	if err := m.BuildModel(testCorpus(t)[:1500]); err != nil {
+		t.Fatal(err)
+	}
+
+	// The container Save wrote before the binary format, with every
+	// occurrence of a suffix listed
+	var container struct {
+		Config   MarkovConfig
+		Chain    map[string][]string
+		Updated  time.Time
+		Negative map[string]map[string]int
+		Ngrams   map[uint64]bool
+		Vocab    map[string]bool
+	}
+	container.Config = m.config
+	container.Updated = m.updated
+	container.Vocab = m.vocab
+	container.Chain = make(map[string][]string, len(m.chain))
+	for prefix, suffixes := range m.chain {
+		for w, n := range suffixes {
+			for range n {
+				container.Chain[prefix] = append(container.Chain[prefix], w)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(container); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewMarkovModel(MarkovConfig{})
+	if err := loaded.Load(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.ValidateModel(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Fingerprint() != m.Fingerprint() {
+		t.Error("legacy model loaded with different counts")
+	}
+	if !reflect.DeepEqual(loaded.config, m.config) {
+		t.Errorf("loaded config %+v, want %+v", loaded.config, m.config)
+	}
+
+	for n := range buf.Len() {
+		if err := NewMarkovModel(MarkovConfig{}).Load(buf.Bytes()[:n]); err == nil {
+			t.Fatalf("Load accepted legacy data truncated to %d of %d bytes", n, buf.Len())
+		}
+	}
+}
+
+func TestLoadTruncatedAndCorrupt(t *testing.T) {
+	corpus := testCorpus(t)
+	m := NewMarkovModel(MarkovConfig{Order: 2, RestoreCase: 0.8})
+	if err := m.BuildModel(corpus[:1500]); err != nil {
+		t.Fatal(err)
+	}
+	data, err := m.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n := range len(data) {
+		if err := NewMarkovModel(MarkovConfig{}).Load(data[:n]); err == nil {
+			t.Fatalf("Load accepted data truncated to %d of %d bytes", n, len(data))
+		}
+	}
+
+	// A damaged byte may still decode to some valid model, but Load must
+	// never panic or keep a model that fails validation
+	corrupt := make([]byte, len(data))
+	for i := range data {
+		for _, b := range []byte{0x00, 0xff, data[i] ^ 0x80} {
+			copy(corrupt, data)
+			corrupt[i] = b
+			loaded := NewMarkovModel(MarkovConfig{})
+			if loaded.Load(corrupt) != nil {
+				continue
+			}
+			if err := loaded.ValidateModel(); err != nil {
+				t.Fatalf("byte %d set to %#x: Load kept an invalid model: %v", i, b, err)
+			}
+		}
+	}
+}
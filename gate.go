@@ -0,0 +1,130 @@
+package gophertext
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// DefaultBoilerplate lists phrases that mark navigation, legal and
+// subscription text scraped along with prose
+var DefaultBoilerplate = []string{
+	"all rights reserved", "click here", "cookie policy", "privacy policy",
+	"terms of service", "terms of use", "subscribe to", "unsubscribe",
+	"sign up for", "read more", "share this", "powered by",
+}
+
+// QualityGate skips training sentences that fail simple quality heuristics,
+// so scraped fragments don't get the same weight as prose. Pass it to
+// BuildModel with WithQualityGate, then read Report for what was skipped.
+type QualityGate struct {
+	MinWords      int      // Skip sentences with fewer words
+	MaxNonLetter  float64  // Skip sentences whose non-space characters are more than this share digits or symbols (0 = no limit)
+	RejectAllCaps bool     // Skip sentences with letters but no lowercase ones
+	Boilerplate   []string // Skip sentences containing any of these phrases, case-insensitively
+
+	mu     sync.Mutex
+	report GateReport
+}
+
+// GateReport counts the sentences a QualityGate examined and skipped
+type GateReport struct {
+	Sentences    int // Sentences examined
+	Skipped      int // Sentences left out of training
+	SkippedWords int // Words in skipped sentences
+
+	TooShort    int // Skipped for having fewer than MinWords words
+	NonLetter   int // Skipped for exceeding MaxNonLetter
+	AllCaps     int // Skipped for being written in capitals
+	Boilerplate int // Skipped for containing a boilerplate phrase
+}
+
+// SkippedRatio is the share of examined sentences that were skipped
+func (r GateReport) SkippedRatio() float64 {
+	if r.Sentences == 0 {
+		return 0
+	}
+	return float64(r.Skipped) / float64(r.Sentences)
+}
+
+// NewQualityGate creates a gate that skips sentences under three words,
+// more than half digits or symbols, in all capitals, or containing
+// DefaultBoilerplate
+func NewQualityGate() *QualityGate {
+	return &QualityGate{
+		MinWords:      3,
+		MaxNonLetter:  0.5,
+		RejectAllCaps: true,
+		Boilerplate:   append([]string(nil), DefaultBoilerplate...),
+	}
+}
+
+// WithQualityGate drops sentences failing g from the corpus before training
+func WithQualityGate(g *QualityGate) TrainOption {
+	return func(o *trainOptions) {
+		o.gate = g
+	}
+}
+
+// Report returns the counts accumulated by every training run using g
+func (g *QualityGate) Report() GateReport {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.report
+}
+
+// filter returns text without the sentences that fail the gate
+func (g *QualityGate) filter(splitter *SentenceSplitter, text string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var kept []string
+	for _, sentence := range splitter.Split(text) {
+		g.report.Sentences++
+		reason := g.reject(sentence)
+		if reason == nil {
+			kept = append(kept, sentence)
+			continue
+		}
+		*reason++
+		g.report.Skipped++
+		g.report.SkippedWords += len(strings.Fields(sentence))
+	}
+	return strings.Join(kept, " ")
+}
+
+// reject returns the report counter for the first check sentence fails, or
+// nil when it passes
+func (g *QualityGate) reject(sentence string) *int {
+	if len(strings.Fields(sentence)) < g.MinWords {
+		return &g.report.TooShort
+	}
+
+	letters, lower, other := 0, 0, 0
+	for _, r := range sentence {
+		switch {
+		case unicode.IsSpace(r):
+		case unicode.IsLetter(r):
+			letters++
+			if unicode.IsLower(r) {
+				lower++
+			}
+		default:
+			other++
+		}
+	}
+	if g.MaxNonLetter > 0 && letters+other > 0 && float64(other)/float64(letters+other) > g.MaxNonLetter {
+		return &g.report.NonLetter
+	}
+	if g.RejectAllCaps && letters > 0 && lower == 0 {
+		return &g.report.AllCaps
+	}
+
+	folded := strings.ToLower(sentence)
+	for _, phrase := range g.Boilerplate {
+		if phrase != "" && strings.Contains(folded, strings.ToLower(phrase)) {
+			return &g.report.Boilerplate
+		}
+	}
+	return nil
+}
@@ -9,31 +9,105 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
-	"unicode"
-
-	"golang.org/x/text/transform"
-	"golang.org/x/text/unicode/norm"
 )
 
-// MarkovConfig holds model configuration
+// MarkovConfig holds model configuration. See MarkovModel.Config for which
+// fields may change after training.
 type MarkovConfig struct {
-	Order          int    // Markov chain order (2-4 recommended)
-	MaxRepeat      int    // Maximum consecutive repeats of same word
-	MinSentenceLen int    // Minimum words per sentence
-	MaxSentenceLen int    // Maximum words per sentence
-	ParagraphBreak int    // Sentences per paragraph
-	StopTokens     string // Sentence-ending punctuation
+	Order          int      // Markov chain order (2-4 recommended, or OrderAuto)
+	MaxRepeat      int      // Maximum consecutive repeats of same word
+	MinSentenceLen int      // Minimum words per sentence
+	MaxSentenceLen int      // Maximum words per sentence
+	ParagraphBreak int      // Typical sentences per paragraph; each paragraph varies around it (0 = one paragraph)
+	StopTokens     string   // Sentence-ending punctuation
+	Language       string   // BCP-47 tag of the corpus language ("" for untagged)
+	PreserveCase   bool     // Keep corpus casing instead of lowercasing during training
+	Abbreviations  []string // Extra abbreviations that never end a sentence
+	Placeholders   PlaceholderConfig
+	EntityMode     bool  // Replace named entities with <PERSON>/<PLACE> tokens during training
+	MaxMemoryBytes int64 // Evict the rarest transitions when the chain grows past this (0 = unlimited)
+
+	// Save-time privacy: Laplace noise of scale PrivacyNoise is added to each
+	// transition count, then transitions below PrivacyThreshold are dropped
+	PrivacyNoise     float64
+	PrivacyThreshold int
+
+	// Sampling weight removed per TrainNegative observation of a transition.
+	// Zero suppresses negatively trained transitions entirely.
+	NegativeWeight float64
+
+	NoveltyN int // Record a digest of corpus n-grams of this length for NoveltyReport (0 = off)
+
+	Smoothing  Smoothing // Spread probability to unseen continuations when scoring and at dead ends
+	SmoothingK float64   // Add-k constant or Kneser-Ney discount (defaults 1 and 0.75)
+
+	Constraints map[string]ConstraintSet // Named constraint sets for WithConstraint
+
+	MaxVocabulary       int // Keep only this many most frequent words, mapping the rest to UnknownToken (0 = unlimited)
+	MinTokenLen         int // Drop tokens of fewer runes, such as stray single characters (0 = no minimum)
+	MaxTokenLen         int // Drop tokens of more runes, such as mashed-together URLs (0 = no maximum)
+	MaxTransitionWeight int // Count each transition at most this many times, so repeated boilerplate can't dominate (0 = unlimited)
+
+	Hyphens     HyphenPolicy // How hyphenated compounds are tokenized
+	RestoreCase float64      // Capitalize words, such as names, that the corpus capitalizes mid-sentence at least this share of the time (0 = off)
+
+	DetectAcronyms bool     // Write words the corpus mostly writes in capitals, such as "NASA", in capitals
+	Acronyms       []string // Words always written in capitals, in addition to detected ones
+
+	MaxWordCount int          // Largest word count one generation may request (0 = DefaultMaxWordCount)
+	Output       OutputPolicy // Whitespace and punctuation of generated text
+	FixAgreement bool         // Repair a/an, doubled determiners and capitals after quotes in English output
+
+	PronounConsistency float64 // Divide the weight of English pronouns that switch person mid-sentence by 1+this (0 = off)
+	EndingBias         float64 // Favor transitions toward a sentence end over the last words before the requested length (0 = off)
+
+	TrimIncompleteSentence bool // Cut output that stops mid-sentence back to its last complete sentence
+
+	ForbiddenStarters []string // Words generated sentences may not start with, such as "and"
+	ForbiddenEnders   []string // Words generated sentences may not end on, such as "of" (see DefaultForbiddenEnders)
+
+	TrainingWorkers int  // Goroutines counting transitions in parallel (0 = GOMAXPROCS)
+	ChunkSize       int  // Words per training chunk (0 = sized from the corpus and worker count)
+	Deterministic   bool // Build the chain identically regardless of scheduling and skip training timestamps, so Save output is reproducible
+
+	Seed int64 // Seed for all randomness, so the same training and calls give the same text (0 = seeded from the clock)
 }
 
+// MarkovModel is a Markov chain text generator. It is safe for concurrent
+// use: generation, Save and the read-only reports take a read lock, while
+// training, Merge, Load and the other mutators take the write lock, so a
+// generation never sees a half-applied update. SetTokenizer and
+// SetEntityRecognizer must be called before the model is shared.
 type MarkovModel struct {
 	config MarkovConfig
-	chain  map[string][]string
+	chain  map[string]map[string]int // Prefix -> suffix -> occurrences
 	mu     sync.RWMutex
 	rules  generationRules
-	pool   sync.Pool // For prefix buffer reuse
+
+	splitter   *SentenceSplitter
+	recognizer EntityRecognizer
+	tokenizer  Tokenizer // Custom tokenization (nil = normalize and split on whitespace)
+
+	sketch     *countMinSketch // Transition frequencies for memory-bounded training
+	chainBytes int64           // Estimated chain size while MaxMemoryBytes is set
+
+	negative map[string]map[string]int // Suppressed transitions from TrainNegative
+	ngrams   map[uint64]bool           // Corpus n-gram digest when NoveltyN is set
+	vocab    map[string]bool           // Words kept when MaxVocabulary is set
+	casing   map[string]caseCount      // Mid-sentence capitalization for RestoreCase and DetectAcronyms
+
+	index   *chainIndex // Lazily built lookup tables for fallbacks
+	indexMu sync.Mutex
+
+	resume  *checkpoint // Progress restored by ResumeTraining
+	updated time.Time   // Last training or decay; the reference point for Decay
+
+	rng    *rand.Rand // Source of all randomness, safe for concurrent use
+	seeded bool       // rng was seeded or injected, so draws follow a sorted order
 }
 
 type generationRules struct {
@@ -43,113 +117,411 @@ type generationRules struct {
 
 // NewMarkovModel creates a new text generator
 func NewMarkovModel(cfg MarkovConfig) *MarkovModel {
-	if cfg.Order < 1 {
-		cfg.Order = 2
+	if cfg.Order < 1 && cfg.Order != OrderAuto {
+		cfg.Order = defaultOrder
 	}
 	if cfg.StopTokens == "" {
 		cfg.StopTokens = ".!?"
 	}
 
-	rand.Seed(time.Now().UnixNano())
-
 	return &MarkovModel{
 		config: cfg,
-		chain:  make(map[string][]string),
+		rng:    newRand(cfg.Seed),
+		seeded: cfg.Seed != 0,
+		chain:  make(map[string]map[string]int),
 		rules: generationRules{
 			forbiddenSequences: make(map[string]bool),
 			alwaysCapitalize:   make(map[string]bool),
 		},
-		pool: sync.Pool{
-			New: func() interface{} {
-				buf := make([]string, 0, cfg.Order*2)
-				return &buf
-			},
-		},
+		splitter: NewSentenceSplitter(cfg.StopTokens, cfg.Abbreviations...),
 	}
 }
 
 // BuildModel processes text and builds the Markov chain
-func (m *MarkovModel) BuildModel(text string) {
-	text = normalizeText(text)
-	words := strings.Fields(text)
-	total := len(words)
-	chunkSize := 4096
+func (m *MarkovModel) BuildModel(text string, opts ...TrainOption) error {
+	o := newTrainOptions(opts)
+	if o.gate != nil {
+		text = o.gate.filter(m.sentenceSplitter(), text)
+	}
+	if o.dryRun != nil {
+		return m.train(m.previewTokens(text), o)
+	}
+	if cfg := m.settings(); (cfg.RestoreCase > 0 || cfg.DetectAcronyms) && !cfg.PreserveCase {
+		m.recordCasing(text)
+	}
+	return m.train(m.trainingTokens(text), o)
+}
 
-	var wg sync.WaitGroup
-	for i := 0; i < total-m.config.Order; i += chunkSize {
-		end := i + chunkSize + m.config.Order
-		if end > total {
-			end = total
+// tokenize normalizes text and splits it into tokens. With MaxVocabulary,
+// words outside the vocabulary fixed by training become UnknownToken; only
+// training, through trainingTokens, fixes the vocabulary.
+func (m *MarkovModel) tokenize(text string) []string {
+	words := m.rawTokens(text)
+	if m.settings().MaxVocabulary > 0 {
+		words = m.capVocabulary(words)
+	}
+	return words
+}
+
+// trainingTokens tokenizes training text, fixing the vocabulary from it
+// when MaxVocabulary is set and no training has fixed it yet
+func (m *MarkovModel) trainingTokens(text string) []string {
+	words := m.rawTokens(text)
+	if m.settings().MaxVocabulary > 0 {
+		words = m.fixVocabulary(words)
+	}
+	return words
+}
+
+// rawTokens tokenizes text without applying MaxVocabulary
+func (m *MarkovModel) rawTokens(text string) []string {
+	// keySpace is reserved for chain keys
+	text = strings.ReplaceAll(text, keySpace, "")
+
+	var words []string
+	if m.tokenizer != nil {
+		words = m.customTokens(text)
+	} else {
+		m.mu.RLock()
+		if m.config.EntityMode {
+			words = m.maskEntities(text)
+		} else {
+			words = joinContractions(strings.Fields(m.normalizeText(text)))
 		}
+		m.mu.RUnlock()
+	}
 
+	cfg := m.settings()
+	if cfg.Hyphens == HyphenSplit {
+		words, _ = splitHyphens(words)
+	}
+	if cfg.Placeholders.Enabled {
+		maskNumbers(words)
+	}
+	return filterTokenLengths(words, cfg)
+}
+
+// settings returns the configuration under the read lock, for callers that
+// don't otherwise hold it
+func (m *MarkovModel) settings() MarkovConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config
+}
+
+// train adds the transitions of a token stream to the chain
+func (m *MarkovModel) train(words []string, o *trainOptions) error {
+	if o.dryRun != nil {
+		m.dryRun(words, o)
+		return nil
+	}
+	m.resolveOrder(words, o)
+	if err := m.checkBudget(len(words), o); err != nil {
+		return err
+	}
+	if o.cooccurrence != nil {
+		o.cooccurrence.add(words)
+	}
+	last := len(words) - m.settings().Order
+	if o.checkpointDir == "" {
+		m.trainRange(words, 0, last)
+	} else if err := m.trainWithCheckpoints(words, last, o); err != nil {
+		return err
+	}
+	if m.settings().Hyphens == HyphenBoth {
+		m.trainHyphenParts(words)
+	}
+	return nil
+}
+
+// trainRange adds the transitions whose prefixes start in [from, to)
+func (m *MarkovModel) trainRange(words []string, from, to int) {
+	cfg := m.settings()
+	chunkSize, workers := trainingPlan(cfg, to-from)
+	budget := cfg.MaxMemoryBytes
+
+	if budget > 0 {
+		m.mu.Lock()
+		if m.sketch == nil {
+			m.sketch = newEvictionSketch(budget)
+			m.chainBytes = estimateChainBytes(m.chain)
+		}
+		m.mu.Unlock()
+	}
+
+	if from < to && !cfg.Deterministic {
+		m.mu.Lock()
+		m.updated = time.Now()
+		m.mu.Unlock()
+	}
+
+	chunks := chunkBounds(from, to, chunkSize)
+	locals := make([]map[string]map[string]int, len(chunks))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i, c := range chunks {
+		if workers == 1 {
+			m.mergeLocal(countTransitions(words, c.start, c.end, cfg.Order), budget)
+			continue
+		}
 		wg.Add(1)
-		go func(chunk []string) {
+		sem <- struct{}{}
+		go func() {
 			defer wg.Done()
-			localChain := make(map[string][]string)
-
-			for i := 0; i < len(chunk)-m.config.Order; i++ {
-				prefix := strings.Join(chunk[i:i+m.config.Order], " ")
-				suffix := chunk[i+m.config.Order]
-				localChain[prefix] = append(localChain[prefix], suffix)
+			defer func() { <-sem }()
+			local := countTransitions(words, c.start, c.end, cfg.Order)
+			if cfg.Deterministic {
+				locals[i] = local
+			} else {
+				m.mergeLocal(local, budget)
 			}
+		}()
+	}
+	wg.Wait()
+
+	// Deterministic training merges in corpus order, so memory-bounded
+	// eviction sees transitions exactly as sequential training would
+	for _, local := range locals {
+		if local != nil {
+			m.mergeLocal(local, budget)
+		}
+	}
+	m.recordNgrams(words, from, to)
+	m.invalidateIndex()
+}
+
+// trainingChunk is a range of prefix start positions
+type trainingChunk struct {
+	start, end int
+}
+
+// chunkBounds splits the prefix start positions [from, to) into chunks of
+// at most size. Chunks partition the positions, so every transition is
+// counted exactly once, by the chunk its prefix starts in, even when its
+// words run past the chunk's end.
+func chunkBounds(from, to, size int) []trainingChunk {
+	var chunks []trainingChunk
+	for start := from; start < to; start += size {
+		chunks = append(chunks, trainingChunk{start, min(start+size, to)})
+	}
+	return chunks
+}
+
+// countTransitions counts the transitions whose prefixes start in
+// [start, end) into a private map. The last of them reads up to Order
+// words beyond end.
+func countTransitions(words []string, start, end, order int) map[string]map[string]int {
+	local := make(map[string]map[string]int)
+	for i := start; i < end; i++ {
+		addTransition(local, joinKey(words[i:i+order]), words[i+order], 1)
+	}
+	return local
+}
+
+// addTransition adds n occurrences of prefix -> word to chain
+func addTransition(chain map[string]map[string]int, prefix, word string, n int) {
+	suffixes := chain[prefix]
+	if suffixes == nil {
+		suffixes = make(map[string]int)
+		chain[prefix] = suffixes
+	}
+	suffixes[word] += n
+}
 
-			m.mu.Lock()
-			for k, v := range localChain {
-				m.chain[k] = append(m.chain[k], v...)
+// mergeLocal adds privately counted transitions to the chain under the lock
+func (m *MarkovModel) mergeLocal(local map[string]map[string]int, budget int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range local {
+		if budget > 0 {
+			existing, ok := m.chain[k]
+			if !ok {
+				m.chainBytes += prefixOverhead + int64(len(k))
 			}
-			m.mu.Unlock()
-		}(words[i:end])
+			for s, n := range v {
+				if _, ok := existing[s]; !ok {
+					m.chainBytes += suffixBytes + int64(len(s))
+				}
+				m.sketch.add(k, s, uint32(n))
+			}
+		}
+		m.addSuffixes(k, v)
 	}
-	wg.Wait()
+	if budget > 0 && m.chainBytes > budget {
+		m.evictRare()
+	}
+}
+
+// Generate outputs words once the model has been trained. Generation starts
+// from a whole prefix, so counts between 1 and Order give Order words.
+func (m *MarkovModel) Generate(wordCount int, opts ...GenerateOption) (string, error) {
+	text, _, err := m.generate(wordCount, newGenerateOptions(opts))
+	return text, err
+}
+
+// GenerateFrom continues prompt with wordCount generated words. The prompt
+// is normalized like training text and included at the start of the output.
+func (m *MarkovModel) GenerateFrom(prompt string, wordCount int, opts ...GenerateOption) (string, error) {
+	o := newGenerateOptions(opts)
+	o.prompt = m.rawTokens(prompt)
+	o.seed = o.prompt
+	if m.settings().MaxVocabulary > 0 {
+		o.seed = m.capVocabulary(slices.Clone(o.prompt))
+	}
+	text, _, err := m.generate(wordCount, o)
+	return text, err
+}
+
+// GenerationStats records how a single generation went
+type GenerationStats struct {
+	Words          int     // Words generated, including the seed prefix
+	Fallbacks      int     // Jumps to a random prefix after a dead end
+	Runs           []int   // Lengths of the uninterrupted stretches between fallbacks
+	EffectiveOrder float64 // Mean number of context words used per lookup
+
+	tokens []string    // Chain tokens emitted, before display formatting
+	trace  []TraceStep // Per-step decisions, recorded for GenerateDebug
 }
 
-// Generate outputs words once the model has been trained
-func (m *MarkovModel) Generate(wordCount int) (string, error) {
+// GenerateWithStats is Generate, additionally reporting how the generation went
+func (m *MarkovModel) GenerateWithStats(wordCount int, opts ...GenerateOption) (string, GenerationStats, error) {
+	return m.generate(wordCount, newGenerateOptions(opts))
+}
+
+func (m *MarkovModel) generate(wordCount int, o *generateOptions) (string, GenerationStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var stats GenerationStats
 	if len(m.chain) == 0 {
-		return "", fmt.Errorf("model not trained")
+		return "", stats, fmt.Errorf("model not trained")
+	}
+	if err := m.checkWordCount(wordCount); err != nil {
+		return "", stats, err
+	}
+	if wordCount == 0 && len(o.seed) == 0 {
+		return "", stats, nil
+	}
+	if o.constraintName != "" {
+		set, ok := m.config.Constraints[o.constraintName]
+		if !ok {
+			return "", stats, fmt.Errorf("unknown constraint set %q", o.constraintName)
+		}
+		o.constraints = set
+	}
+	if len(o.anchorWords) > 0 {
+		o.anchors = make(map[string]bool)
+		for _, w := range o.anchorWords {
+			for _, token := range strings.Fields(m.normalizeText(w)) {
+				o.anchors[token] = true
+			}
+		}
+	}
+
+	if len(o.boostWords) > 0 {
+		o.boosts = make(map[string]float64)
+		for w, factor := range o.boostWords {
+			for _, token := range strings.Fields(m.normalizeText(w)) {
+				o.boosts[token] = factor
+			}
+		}
+	}
+	m.normalizeExclusions(o)
+	o.starters = m.boundaryWords(m.config.ForbiddenStarters)
+	o.enders = m.boundaryWords(m.config.ForbiddenEnders)
+
+	if !o.constraints.empty() || o.exclusions != nil {
+		return m.generateConstrained(wordCount, o)
 	}
 
 	var result strings.Builder
 	result.Grow(wordCount * 6)
 
-	currentPrefix := m.randomPrefix()
-	words := strings.Fields(currentPrefix)
-	result.WriteString(currentPrefix)
+	var currentPrefix string
+	var words []string
+	if len(o.seed) > 0 {
+		words = append(words, o.seed...)
+		wordCount += len(words)
+	} else if o.diversity != nil {
+		currentPrefix = m.diverseStart(o.diversity)
+		words = splitKey(currentPrefix)
+	} else {
+		currentPrefix = m.startPrefix()
+		words = splitKey(currentPrefix)
+	}
+	result.WriteString(strings.Join(words, " "))
 
-	// Normalize initial prefix for tracking
+	// Track the lookup key one word per element
 	prefixBuffer := make([]string, 0, m.config.Order*2)
-	prefixBuffer = append(prefixBuffer, strings.ToLower(currentPrefix))
+	if len(o.seed) > 0 {
+		prefixBuffer = append(prefixBuffer, m.promptContext(words)...)
+	} else if len(words) > m.config.Order {
+		prefixBuffer = append(prefixBuffer, words[len(words)-m.config.Order:]...)
+	} else {
+		prefixBuffer = append(prefixBuffer, words...)
+	}
 
 	wordsGenerated := len(words)
 	sentenceCount := 0
-	paragraphCount := 0
 	lastWord := ""
 	repeatCount := 0
+	run := 0
+	lookups, contextWords := 0, 0
+	stats.tokens = append(stats.tokens, words...)
 
 	for wordsGenerated < wordCount {
 		// Get next word using normalized prefix
-		normalizedPrefix := strings.Join(prefixBuffer, " ")
-		possible := m.chain[normalizedPrefix]
-
-		if len(possible) == 0 {
-			// Fallback to random prefix
-			currentPrefix = m.randomPrefix()
-			prefixBuffer = strings.Fields(strings.ToLower(currentPrefix))
-			possible = m.chain[currentPrefix]
-			if len(possible) == 0 {
-				return "", fmt.Errorf("broken chain")
+		normalizedPrefix := joinKey(prefixBuffer)
+		lookups++
+		contextWords += len(prefixBuffer)
+		m.noteSentence(stats.tokens, o)
+		m.noteRemaining(wordCount-wordsGenerated, o)
+		nextWord, ok := m.sample(normalizedPrefix, m.chain[normalizedPrefix], o)
+		step := TraceStep{Prefix: normalizedPrefix, Candidates: suffixTotal(m.chain[normalizedPrefix])}
+
+		// Smoothed models back off to shorter contexts before giving up
+		if !ok && m.config.Smoothing != SmoothingNone {
+			var context int
+			if nextWord, context, ok = m.backoffSample(prefixBuffer, o); ok {
+				contextWords -= len(prefixBuffer) - context
 			}
 		}
 
-		nextWord := possible[rand.Intn(len(possible))]
+		if !ok {
+			if o.fallback == FallbackAbort {
+				return "", stats, ErrDeadEnd
+			}
+			stats.Fallbacks++
+			stats.Runs = append(stats.Runs, run)
+			run = 0
+
+			context := 0
+			for attempt := 0; !ok; attempt++ {
+				if attempt == 8 {
+					return "", stats, fmt.Errorf("broken chain")
+				}
+				currentPrefix, context = m.restartPrefix(o.fallback, prefixBuffer, stats.tokens)
+				nextWord, ok = m.sample(currentPrefix, m.chain[currentPrefix], o)
+			}
+			contextWords -= len(prefixBuffer) - context
+			prefixBuffer = splitKey(currentPrefix)
+			step.Fallback = true
+			step.Prefix = currentPrefix
+			step.Candidates = suffixTotal(m.chain[currentPrefix])
+		}
 
 		// Apply rules and get display version
-		displayWord := m.applyGenerationRules(nextWord, &words, &result,
-			&sentenceCount, &paragraphCount, &lastWord, &repeatCount)
+		displayWord, rule := m.applyGenerationRules(nextWord, &words, &result,
+			&sentenceCount, &lastWord, &repeatCount)
+		if o.debug {
+			step.Word = displayWord
+			step.Rule = rule
+			stats.trace = append(stats.trace, step)
+		}
 
 		// Update tracking buffers
 		words = append(words, displayWord)
-		prefixBuffer = append(prefixBuffer, strings.ToLower(nextWord))
+		prefixBuffer = append(prefixBuffer, nextWord)
 		if len(prefixBuffer) > m.config.Order {
 			prefixBuffer = prefixBuffer[1:]
 		}
@@ -160,83 +532,200 @@ func (m *MarkovModel) Generate(wordCount int) (string, error) {
 		}
 		result.WriteString(displayWord)
 		wordsGenerated++
+		run++
+		stats.tokens = append(stats.tokens, nextWord)
+	}
+	stats.Runs = append(stats.Runs, run)
+	stats.Words = wordsGenerated
+	if lookups > 0 {
+		stats.EffectiveOrder = float64(contextWords) / float64(lookups)
 	}
 
-	return postProcessText(result.String()), nil
+	return m.postProcessText(result.String(), o), stats, nil
 }
 
 // Update applyGenerationRules to track sentence length
 func (m *MarkovModel) applyGenerationRules(nextWord string, words *[]string, result *strings.Builder,
-	sentenceCount, paragraphCount *int, lastWord *string, repeatCount *int) string {
+	sentenceCount *int, lastWord *string, repeatCount *int) (string, string) {
 
 	// Track sentence length
 	*sentenceCount++
+	endedSentence := m.splitter.IsTerminal(*lastWord)
 
 	// Rule 1: Prevent word repetition
 	if nextWord == *lastWord {
 		*repeatCount++
 		if *repeatCount > m.config.MaxRepeat {
-			return (*words)[rand.Intn(len(*words))]
+			return (*words)[m.rng.Intn(len(*words))], RuleRepeat
 		}
 	} else {
 		*repeatCount = 0
 	}
 	*lastWord = nextWord
 
-	// Rule 2: Enforce sentence length
-	if *sentenceCount >= m.config.MaxSentenceLen {
+	// Rule 2: Natural sentence endings restart the length count
+	if endedSentence {
+		*sentenceCount = 1
+		return capitalizeFirst(nextWord), RuleSentenceEnd
+	}
+
+	// Rule 3: Enforce sentence length
+	if m.config.MaxSentenceLen > 0 && *sentenceCount >= m.config.MaxSentenceLen {
 		result.WriteString(". ")
 		*sentenceCount = 0
-		*paragraphCount++
-
-		// Add paragraph break
-		if *paragraphCount%m.config.ParagraphBreak == 0 {
-			result.WriteString("\n\n")
-		}
 
 		// Capitalize next word
-		return strings.Title(nextWord)
+		return capitalizeFirst(nextWord), RuleMaxLength
 	}
 
-	return nextWord
+	return nextWord, ""
 }
 
 // Update postProcessText to remove redundant formatting
-func postProcessText(text string) string {
+func (m *MarkovModel) postProcessText(text string, o *generateOptions) string {
 	// Simple cleanup instead of sentence splitting
-	return strings.Join(strings.Fields(text), " ")
+	words := strings.Fields(text)
+	// Prompt words outside the vocabulary are printed as given
+	for i, w := range o.prompt {
+		if i < len(words) && words[i] == UnknownToken {
+			words[i] = w
+		}
+	}
+	if m.config.TrimIncompleteSentence {
+		words = m.trimIncomplete(words)
+	}
+	if m.config.Placeholders.Enabled {
+		for i, w := range words {
+			words[i] = m.config.Placeholders.synthesize(w, m.rng)
+		}
+	}
+	if len(o.entities) > 0 {
+		for i, w := range words {
+			words[i] = substituteEntities(w, o.entities, m.rng)
+		}
+	}
+	if len(o.synonyms) > 0 && o.synonymRate > 0 {
+		for i := len(o.seed); i < len(words); i++ {
+			words[i] = o.synonyms.substitute(words[i], o.synonymRate, m.rng)
+		}
+	}
+	m.restoreCasing(words)
+	if m.config.Hyphens != HyphenKeep {
+		words = joinHyphenPieces(words)
+	}
+	if m.config.FixAgreement && isEnglish(m.config.Language) {
+		words = fixAgreement(words)
+	}
+	profile := profileFor(m.config.Language)
+	format := func(words []string) string {
+		return profile.apply(words, m.config.Output)
+	}
+	if m.config.ParagraphBreak > 0 && len(words) > 0 {
+		return m.layParagraphs(words, format)
+	}
+	return format(words)
 }
 
 func (m *MarkovModel) Save() ([]byte, error) {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	if err := enc.Encode(struct {
-		Config MarkovConfig
-		Chain  map[string][]string
-	}{
-		Config: m.config,
-		Chain:  m.chain,
-	}); err != nil {
-		return nil, err
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return encodeModel(modelMeta{
+		Config:   m.config,
+		Updated:  m.updated,
+		Negative: m.negative,
+		Ngrams:   m.ngrams,
+		Vocab:    m.vocab,
+		Casing:   m.casing,
+	}, m.savedChain())
+}
+
+// savedChain is the chain as it leaves the process, with privacy applied
+func (m *MarkovModel) savedChain() map[string]map[string]int {
+	if m.config.PrivacyNoise > 0 || m.config.PrivacyThreshold > 1 {
+		return m.privatizedChain()
 	}
-	return buf.Bytes(), nil
+	return m.chain
 }
 
+// Load restores a model written by Save. Models saved in the older gob
+// encoding are still accepted.
 func (m *MarkovModel) Load(data []byte) error {
+	var meta modelMeta
+	var chain map[string]map[string]int
+	var err error
+	if bytes.HasPrefix(data, []byte(formatMagic)) {
+		meta, chain, _, err = decodeModel(data, -1)
+	} else {
+		meta, chain, err = decodeGob(data)
+	}
+	if err != nil {
+		return err
+	}
+	return m.restore(meta, chain)
+}
+
+// decodeGob reads the original gob-encoded model files
+func decodeGob(data []byte) (modelMeta, map[string]map[string]int, error) {
 	var container struct {
-		Config MarkovConfig
-		Chain  map[string][]string
+		Config   MarkovConfig
+		Chain    map[string][]string
+		Updated  time.Time
+		Negative map[string]map[string]int
+		Ngrams   map[uint64]bool
+		Vocab    map[string]bool
 	}
 
 	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&container); err != nil {
-		return err
+		return modelMeta{}, nil, err
+	}
+	chain := make(map[string]map[string]int, len(container.Chain))
+	for prefix, suffixes := range container.Chain {
+		chain[prefix] = countSuffixes(suffixes)
 	}
 
-	m.config = container.Config
-	m.chain = container.Chain
+	return modelMeta{
+		Config:   container.Config,
+		Updated:  container.Updated,
+		Negative: container.Negative,
+		Ngrams:   container.Ngrams,
+		Vocab:    container.Vocab,
+	}, chain, nil
+}
+
+// restore validates loaded data and replaces the model's state with it.
+// Invalid data leaves the model untouched. Generations already running
+// finish on the old state.
+func (m *MarkovModel) restore(meta modelMeta, chain map[string]map[string]int) error {
+	if chain == nil {
+		chain = make(map[string]map[string]int)
+	}
+	if err := validateModel(meta.Config, chain, meta.Negative); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = meta.Config
+	m.chain = chain
+	m.updated = meta.Updated
+	m.negative = meta.Negative
+	m.ngrams = meta.Ngrams
+	m.vocab = meta.Vocab
+	m.casing = meta.Casing
+	m.restored()
 	return nil
 }
 
+// restored rebuilds the state derived from the configuration and chain
+// after restore or ApplyDelta replaced them. Callers hold the write lock.
+func (m *MarkovModel) restored() {
+	m.capTransitions()
+	m.invalidateIndex()
+	m.splitter = NewSentenceSplitter(m.config.StopTokens, m.config.Abbreviations...)
+	if m.config.Seed != 0 {
+		m.rng, m.seeded = newRand(m.config.Seed), true
+	}
+}
+
 // LoadEmbedded adds embedded model support
 func LoadEmbedded(fs embed.FS, path string) (*MarkovModel, error) {
 	fmt.Println(os.Getwd())
@@ -250,23 +739,22 @@ func LoadEmbedded(fs embed.FS, path string) (*MarkovModel, error) {
 }
 
 // Text normalization and post-processing
-func normalizeText(text string) string {
-	// Remove diacritics and normalize text
-	t := transform.Chain(norm.NFD, transform.RemoveFunc(func(r rune) bool {
-		return unicode.Is(unicode.Mn, r) // Mn: nonspacing marks
-	}), norm.NFC)
+func (m *MarkovModel) normalizeText(text string) string {
+	result := normalizeApostrophes(nfc(text))
+	if profileFor(m.config.Language).stripMarks {
+		result = stripMarks(result)
+	}
 
-	result, _, _ := transform.String(t, text)
+	if m.config.PreserveCase {
+		return result
+	}
 	return strings.ToLower(result)
 }
 
 // Helper methods
 func (m *MarkovModel) randomPrefix() string {
-	prefixes := make([]string, 0, len(m.chain))
-	for k := range m.chain {
-		prefixes = append(prefixes, k)
-	}
-	return prefixes[rand.Intn(len(prefixes))]
+	prefixes := m.prefixIndex().prefixes
+	return prefixes[m.rng.Intn(len(prefixes))]
 }
 
 // SaveModelToFile saves the trained model to disk
@@ -0,0 +1,100 @@
+package gophertext
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// testCorpus returns the literature corpus shipped with the examples
+func testCorpus(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile("example/corpus/literature.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestOrders(t *testing.T) {
+	corpus := testCorpus(t)
+	for _, order := range []int{1, 2, 3, 4, 5} {
+		t.Run(fmt.Sprintf("order %d", order), func(t *testing.T) {
+			m := NewMarkovModel(MarkovConfig{Order: order})
+			if err := m.BuildModel(corpus); err != nil {
+				t.Fatal(err)
+			}
+			for prefix := range m.chain {
+				if err := validPrefix(prefix, order); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i := 0; i < 10; i++ {
+				_, stats, err := m.GenerateWithStats(30)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if stats.Words != 30 {
+					t.Fatalf("generated %d words, want 30", stats.Words)
+				}
+				if stats.Fallbacks == 0 && stats.EffectiveOrder != float64(order) {
+					t.Errorf("EffectiveOrder = %v without fallbacks, want %d", stats.EffectiveOrder, order)
+				}
+			}
+		})
+	}
+}
+
+func TestParallelTraining(t *testing.T) {
+	corpus := testCorpus(t)
+	sequential := NewMarkovModel(MarkovConfig{Order: 2, TrainingWorkers: 1})
+	if err := sequential.BuildModel(corpus); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct{ chunkSize, workers int }{
+		{1, 4},
+		{7, 2},
+		{100, 3},
+		{1000, 8},
+		{4096, 4},
+		{1 << 20, 2},
+		{0, 0},
+	} {
+		t.Run(fmt.Sprintf("chunk %d workers %d", tc.chunkSize, tc.workers), func(t *testing.T) {
+			m := NewMarkovModel(MarkovConfig{Order: 2, ChunkSize: tc.chunkSize, TrainingWorkers: tc.workers})
+			if err := m.BuildModel(corpus); err != nil {
+				t.Fatal(err)
+			}
+			// Workers merge in any order, so compare counts rather than layout
+			if len(m.chain) != len(sequential.chain) || m.chainDigest() != sequential.chainDigest() {
+				t.Errorf("chain differs from sequential training: %d prefixes, want %d", len(m.chain), len(sequential.chain))
+			}
+		})
+	}
+}
+
+func TestDeterministicSave(t *testing.T) {
+	corpus := testCorpus(t)
+	for _, workers := range []int{1, 2, 4, 8} {
+		// The saved configuration includes TrainingWorkers, so only runs with
+		// the same worker count can be byte-identical
+		var saved [2][]byte
+		for run := range saved {
+			m := NewMarkovModel(MarkovConfig{Order: 2, Deterministic: true, ChunkSize: 512, TrainingWorkers: workers})
+			if err := m.BuildModel(corpus); err != nil {
+				t.Fatal(err)
+			}
+			data, err := m.Save()
+			if err != nil {
+				t.Fatal(err)
+			}
+			saved[run] = data
+		}
+		if !bytes.Equal(saved[0], saved[1]) {
+			t.Errorf("workers %d: saved models differ", workers)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package gophertext
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GraphOptions controls ExportJSONGraph
+type GraphOptions struct {
+	MaxEdges  int // Heaviest transitions kept (default 200)
+	MinWeight int // Transitions seen fewer times are dropped
+}
+
+// GraphNode is one chain prefix in an exported graph
+type GraphNode struct {
+	ID     string `json:"id"`     // Chain prefix, its words separated by spaces
+	Weight int    `json:"weight"` // Transitions out of the prefix in training
+}
+
+// GraphLink is one transition in an exported graph. Target is the prefix
+// the chain moves to after emitting Word.
+type GraphLink struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Word   string `json:"word"`
+	Value  int    `json:"value"` // Times the transition was seen
+}
+
+// Graph is the node-link form of a model written by ExportJSONGraph
+type Graph struct {
+	Order int         `json:"order"`
+	Nodes []GraphNode `json:"nodes"`
+	Links []GraphLink `json:"links"`
+}
+
+// ExportJSONGraph writes the heaviest transitions of the chain as a
+// node-link graph in the layout D3's force simulation expects:
+//
+//	{"order": 2, "nodes": [{"id": "the cat", "weight": 3}, ...],
+//	 "links": [{"source": "the cat", "target": "cat sat", "word": "sat", "value": 2}, ...]}
+//
+// Only nodes touched by a kept link are listed. Output is sorted, so the
+// same model always exports the same graph. Privacy settings apply as they
+// do for Save.
+func (m *MarkovModel) ExportJSONGraph(w io.Writer, opts GraphOptions) error {
+	if opts.MaxEdges <= 0 {
+		opts.MaxEdges = 200
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	chain := m.savedChain()
+
+	var links []GraphLink
+	for prefix, suffixes := range chain {
+		tail := splitKey(prefix)[1:]
+		for word, n := range suffixes {
+			if n < opts.MinWeight {
+				continue
+			}
+			links = append(links, GraphLink{
+				Source: prefix,
+				Target: joinKey(append(tail[:len(tail):len(tail)], word)),
+				Word:   word,
+				Value:  n,
+			})
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		a, b := links[i], links[j]
+		if a.Value != b.Value {
+			return a.Value > b.Value
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Word < b.Word
+	})
+	if len(links) > opts.MaxEdges {
+		links = links[:opts.MaxEdges]
+	}
+
+	graph := Graph{Order: m.config.Order, Nodes: []GraphNode{}, Links: links}
+	seen := make(map[string]bool)
+	for i, l := range links {
+		for _, id := range []string{l.Source, l.Target} {
+			if !seen[id] {
+				seen[id] = true
+				graph.Nodes = append(graph.Nodes, GraphNode{ID: graphID(id), Weight: suffixTotal(chain[id])})
+			}
+		}
+		links[i].Source, links[i].Target = graphID(l.Source), graphID(l.Target)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].ID < graph.Nodes[j].ID })
+	if graph.Links == nil {
+		graph.Links = []GraphLink{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(graph); err != nil {
+		return fmt.Errorf("failed to encode graph: %w", err)
+	}
+	return nil
+}
+
+// graphID spells a chain key as its words separated by plain spaces
+func graphID(key string) string {
+	return strings.Join(splitKey(key), " ")
+}
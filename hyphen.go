@@ -0,0 +1,106 @@
+package gophertext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HyphenPolicy selects how hyphenated compounds such as "well-known" are
+// tokenized
+type HyphenPolicy int
+
+const (
+	HyphenKeep  HyphenPolicy = iota // One token: "well-known"
+	HyphenSplit                     // One token per part, "well-" and "known", rejoined on output
+	HyphenBoth                      // Learn the compound and its parts, so either can be generated
+)
+
+// splitHyphens splits every compound into parts at hyphens between two
+// letters. Each part but the last keeps its hyphen, so output can join the
+// parts back up. pieces reports which tokens came from a compound.
+func splitHyphens(words []string) (split []string, pieces []bool) {
+	split = make([]string, 0, len(words))
+	pieces = make([]bool, 0, len(words))
+	for _, w := range words {
+		start, compound := 0, false
+		for i := 1; i < len(w)-1; i++ {
+			if w[i] != '-' || !letterBefore(w, i) || !letterAfter(w, i+1) {
+				continue
+			}
+			split = append(split, w[start:i+1])
+			pieces = append(pieces, true)
+			start, compound = i+1, true
+		}
+		split = append(split, w[start:])
+		pieces = append(pieces, compound)
+	}
+	return split, pieces
+}
+
+// letterBefore reports whether the rune ending at w[i] is a letter
+func letterBefore(w string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(w[:i])
+	return unicode.IsLetter(r)
+}
+
+// letterAfter reports whether the rune starting at w[i] is a letter
+func letterAfter(w string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(w[i:])
+	return unicode.IsLetter(r)
+}
+
+// isHyphenPiece reports whether word is the leading part of a split
+// compound, such as "well-"
+func isHyphenPiece(word string) bool {
+	return len(word) > 1 && strings.HasSuffix(word, "-") && letterBefore(word, len(word)-1)
+}
+
+// joinHyphenPieces glues split compound parts back onto the word that
+// follows them. A part left dangling at the end loses its hyphen.
+func joinHyphenPieces(words []string) []string {
+	joined := words[:0]
+	pending := ""
+	for _, w := range words {
+		if isHyphenPiece(w) {
+			pending += w
+			continue
+		}
+		joined = append(joined, pending+w)
+		pending = ""
+	}
+	if pending != "" {
+		joined = append(joined, strings.TrimSuffix(pending, "-"))
+	}
+	return joined
+}
+
+// trainHyphenParts adds the transitions of the split form of words that
+// involve a compound part, for HyphenBoth. Transitions away from compounds
+// are the same in both forms and were already learned.
+func (m *MarkovModel) trainHyphenParts(words []string) {
+	cfg := m.settings()
+	split, pieces := splitHyphens(words)
+	local := make(map[string]map[string]int)
+	for i := 0; i+cfg.Order < len(split); i++ {
+		if !containsPiece(pieces[i : i+cfg.Order+1]) {
+			continue
+		}
+		addTransition(local, joinKey(split[i:i+cfg.Order]), split[i+cfg.Order], 1)
+	}
+	if len(local) == 0 {
+		return
+	}
+	m.mergeLocal(local, cfg.MaxMemoryBytes)
+	m.invalidateIndex()
+}
+
+// containsPiece reports whether any token of a window came from a compound
+func containsPiece(pieces []bool) bool {
+	for _, p := range pieces {
+		if p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package gophertext
+
+import "sort"
+
+// chainIndex holds lookup tables derived from the chain for fallback
+// strategies. It is built on first use and discarded whenever the chain
+// changes.
+type chainIndex struct {
+	prefixes []string            // Every prefix, sorted
+	starts   []string            // Prefixes that begin a sentence
+	byEnding map[string][]string // Last k words (k < Order) -> prefixes ending with them
+	byWord   map[string][]string // Word -> prefixes containing it
+
+	smoothing *smoothingTables     // Built on first use by smoothed models
+	endings   map[string][]float64 // Prefix -> chance of a sentence end after each number of steps, filled in by EndingBias
+}
+
+// prefixIndex returns the chain index, building it if needed
+func (m *MarkovModel) prefixIndex() *chainIndex {
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	if m.index == nil {
+		m.index = m.buildIndex()
+	}
+	return m.index
+}
+
+// invalidateIndex discards the chain index after the chain changed
+func (m *MarkovModel) invalidateIndex() {
+	m.indexMu.Lock()
+	m.index = nil
+	m.indexMu.Unlock()
+}
+
+func (m *MarkovModel) buildIndex() *chainIndex {
+	idx := &chainIndex{
+		byEnding: make(map[string][]string),
+		byWord:   make(map[string][]string),
+	}
+	idx.prefixes = make([]string, 0, len(m.chain))
+	for prefix := range m.chain {
+		idx.prefixes = append(idx.prefixes, prefix)
+	}
+	// Sorted lists keep seeded generations reproducible
+	sort.Strings(idx.prefixes)
+
+	isStart := make(map[string]bool)
+	for _, prefix := range idx.prefixes {
+		suffixes := m.chain[prefix]
+		words := splitKey(prefix)
+		for i, w := range words {
+			if !containsWord(words[:i], w) {
+				idx.byWord[w] = append(idx.byWord[w], prefix)
+			}
+		}
+		for k := 1; k < len(words); k++ {
+			key := joinKey(words[len(words)-k:])
+			idx.byEnding[key] = append(idx.byEnding[key], prefix)
+		}
+
+		// A prefix following a terminal word starts a sentence
+		if !m.splitter.IsTerminal(words[0]) {
+			continue
+		}
+		tail := words[1:]
+		for s := range suffixes {
+			next := joinKey(append(tail, s))
+			if _, ok := m.chain[next]; ok && !isStart[next] {
+				isStart[next] = true
+				idx.starts = append(idx.starts, next)
+			}
+		}
+	}
+	sort.Strings(idx.starts)
+	return idx
+}
+
+func containsWord(words []string, w string) bool {
+	for _, x := range words {
+		if x == w {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package gophertext
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ExportJSON writes the chain as JSON for inspection and version control:
+//
+//	{
+//	"order": 2,
+//	"chain": {
+//	"the cat": {"ran": 1, "sat": 2},
+//	...
+//	}
+//	}
+//
+// Prefixes and suffixes are sorted and each prefix sits on its own line, so
+// exporting the same model twice gives identical output and a retrained
+// model diffs line by line. Privacy settings apply as they do for Save.
+func (m *MarkovModel) ExportJSON(w io.Writer) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	chain := m.savedChain()
+	prefixes := make([]string, 0, len(chain))
+	for prefix := range chain {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	var line bytes.Buffer
+	enc := json.NewEncoder(&line)
+	enc.SetEscapeHTML(false)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "{\n\"order\": %d,\n\"chain\": {\n", m.config.Order)
+	for i, prefix := range prefixes {
+		line.Reset()
+		if err := enc.Encode(prefix); err != nil {
+			return fmt.Errorf("failed to encode prefix: %w", err)
+		}
+		line.Truncate(line.Len() - 1)
+		line.WriteString(": ")
+		// encoding/json writes map keys in sorted order
+		if err := enc.Encode(chain[prefix]); err != nil {
+			return fmt.Errorf("failed to encode suffixes of %q: %w", prefix, err)
+		}
+		if i < len(prefixes)-1 {
+			line.Truncate(line.Len() - 1)
+			line.WriteString(",\n")
+		}
+		bw.Write(line.Bytes())
+	}
+	bw.WriteString("}\n}\n")
+	return bw.Flush()
+}
@@ -0,0 +1,128 @@
+package gophertext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Chain keys are the words of a prefix joined by single spaces. Tokens from
+// a custom Tokenizer may contain spaces themselves, so inside a key those
+// are stored as keySpace, which tokenization strips from the text and from
+// every custom token. Build and split keys only with joinKey and splitKey;
+// the encoding is then unambiguous whatever the tokens contain, and keys of
+// whitespace-free tokens are plain readable text.
+const keySpace = "\x00"
+
+// joinKey encodes words as a chain key
+func joinKey(words []string) string {
+	for _, w := range words {
+		if strings.Contains(w, " ") {
+			escaped := make([]string, len(words))
+			for i, w := range words {
+				escaped[i] = strings.ReplaceAll(w, " ", keySpace)
+			}
+			return strings.Join(escaped, " ")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitKey decodes a chain key into its words
+func splitKey(key string) []string {
+	if key == "" {
+		return nil
+	}
+	words := strings.Split(key, " ")
+	if strings.Contains(key, keySpace) {
+		for i, w := range words {
+			words[i] = strings.ReplaceAll(w, keySpace, " ")
+		}
+	}
+	return words
+}
+
+// escapeCountWord encodes a word as one field of a count file, which splits
+// n-grams on any whitespace: spaces become keySpace as in chain keys, other
+// whitespace is written as \uXXXX and backslashes are doubled
+func escapeCountWord(w string) string {
+	if !strings.ContainsFunc(w, func(r rune) bool { return r == '\\' || (r != ' ' && unicode.IsSpace(r)) }) {
+		return strings.ReplaceAll(w, " ", keySpace)
+	}
+	var b strings.Builder
+	for _, r := range w {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == ' ':
+			b.WriteString(keySpace)
+		case unicode.IsSpace(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// unescapeCountWord decodes a count file field. Backslashes that don't
+// start an escape are kept as they are.
+func unescapeCountWord(field string) string {
+	if !strings.ContainsAny(field, `\`+keySpace) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		switch c := field[i]; {
+		case c == keySpace[0]:
+			b.WriteByte(' ')
+		case c == '\\' && i+1 < len(field) && field[i+1] == '\\':
+			b.WriteByte('\\')
+			i++
+		case c == '\\' && i+5 < len(field) && field[i+1] == 'u':
+			if r, err := strconv.ParseUint(field[i+2:i+6], 16, 32); err == nil {
+				b.WriteRune(rune(r))
+				i += 5
+			} else {
+				b.WriteByte(c)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Tokenizer splits training text into tokens. A token may contain spaces or
+// other whitespace, such as a multi-word name kept as a single unit.
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// TokenizerFunc adapts a function to the Tokenizer interface
+type TokenizerFunc func(text string) []string
+
+// Tokenize calls f
+func (f TokenizerFunc) Tokenize(text string) []string {
+	return f(text)
+}
+
+// SetTokenizer replaces the built-in normalization and whitespace splitting
+// used for training text and prompts. Empty tokens are dropped. Set it
+// before training and again after Load, as the tokenizer isn't saved.
+func (m *MarkovModel) SetTokenizer(t Tokenizer) {
+	m.tokenizer = t
+}
+
+// customTokens runs the custom tokenizer, dropping empty tokens and the
+// bytes reserved for key encoding
+func (m *MarkovModel) customTokens(text string) []string {
+	var words []string
+	for _, w := range m.tokenizer.Tokenize(text) {
+		if w = strings.ReplaceAll(w, keySpace, ""); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
@@ -0,0 +1,37 @@
+package gophertext
+
+import "testing"
+
+func TestKeySpaceInCorpus(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		tokenizer Tokenizer
+	}{
+		{"default", nil},
+		{"custom", TokenizerFunc(func(text string) []string { return []string{"new\x00york", "is", "big", "new york", "is", "old"} })},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMarkovModel(MarkovConfig{Order: 1})
+			if tc.tokenizer != nil {
+				m.SetTokenizer(tc.tokenizer)
+			}
+			if err := m.BuildModel("the a\x00b cat sat on the a\x00b mat.\x00 The cat ran."); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.ValidateModel(); err != nil {
+				t.Fatal(err)
+			}
+			data, err := m.Save()
+			if err != nil {
+				t.Fatal(err)
+			}
+			loaded := NewMarkovModel(MarkovConfig{})
+			if err := loaded.Load(data); err != nil {
+				t.Fatal(err)
+			}
+			if loaded.Fingerprint() != m.Fingerprint() {
+				t.Error("loaded model differs from the saved one")
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package gophertext
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Stopword profiles used for lightweight language detection. Short function
+// words are frequent enough that a paragraph or two is usually decisive.
+var stopwordProfiles = map[string][]string{
+	"en": {"the", "and", "of", "to", "in", "is", "that", "it", "was", "for", "with", "as", "his", "he", "be", "on", "by", "at", "which", "this", "had", "not", "are", "but", "from", "have", "they", "you", "were", "her"},
+	"fr": {"le", "la", "les", "de", "des", "du", "et", "un", "une", "est", "que", "qui", "dans", "pour", "pas", "sur", "au", "aux", "avec", "il", "elle", "ne", "se", "ce", "sont", "mais", "nous", "vous", "par", "leur"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "von", "mit", "sich", "des", "auf", "für", "im", "dem", "auch", "es", "an", "er", "sie", "wir", "ich", "wie", "aus", "bei", "oder", "noch"},
+	"es": {"el", "la", "los", "las", "de", "del", "y", "que", "en", "un", "una", "es", "por", "con", "para", "no", "se", "su", "al", "lo", "como", "más", "pero", "sus", "le", "ya", "este", "sí", "porque", "muy"},
+	"it": {"il", "la", "di", "che", "e", "un", "una", "è", "per", "non", "in", "con", "del", "della", "si", "le", "lo", "gli", "ma", "da", "sono", "al", "come", "anche", "più", "nel", "nella", "questo", "suo", "dei"},
+	"pt": {"o", "a", "os", "as", "de", "do", "da", "dos", "das", "e", "que", "em", "um", "uma", "não", "com", "para", "por", "se", "na", "no", "mais", "como", "mas", "foi", "ao", "ele", "ela", "são", "seu"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "in", "op", "te", "zijn", "met", "voor", "er", "maar", "ook", "als", "aan", "bij", "om", "dan", "nog", "wel", "ik", "je", "hij", "ze", "was", "naar"},
+}
+
+var stopwordSets = func() map[string]map[string]bool {
+	sets := make(map[string]map[string]bool, len(stopwordProfiles))
+	for lang, words := range stopwordProfiles {
+		set := make(map[string]bool, len(words))
+		for _, w := range words {
+			set[w] = true
+		}
+		sets[lang] = set
+	}
+	return sets
+}()
+
+var paragraphSep = regexp.MustCompile(`\n\s*\n`)
+
+// DetectLanguage guesses the base language of text from stopword frequencies.
+// It returns the BCP-47 base tag and a confidence in [0, 1], or "" when the
+// text is too short or too ambiguous to call.
+func DetectLanguage(text string) (string, float64) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) < 3 {
+		return "", 0
+	}
+
+	best, second := "", 0
+	bestHits := 0
+	for lang, set := range stopwordSets {
+		hits := 0
+		for _, w := range words {
+			if set[w] {
+				hits++
+			}
+		}
+		if hits > bestHits || (hits == bestHits && lang < best) {
+			best, second, bestHits = lang, bestHits, hits
+		} else if hits > second {
+			second = hits
+		}
+	}
+	if bestHits < 2 {
+		return "", 0
+	}
+
+	// Confidence reflects both how decisive the winner is and how much of
+	// the text it explains
+	margin := float64(bestHits-second) / float64(bestHits)
+	coverage := float64(bestHits) / float64(len(words))
+	confidence := margin * coverage * 4
+	if confidence > 1 {
+		confidence = 1
+	}
+	if confidence < 0.1 {
+		return "", confidence
+	}
+	return best, confidence
+}
+
+// SplitByLanguage routes each paragraph of text to its detected language.
+// Paragraphs too short to classify (headings, captions) follow the language
+// of the paragraph before them.
+func SplitByLanguage(text string) map[string]string {
+	paragraphs := paragraphSep.Split(text, -1)
+	langs := make([]string, len(paragraphs))
+
+	current := ""
+	for i, p := range paragraphs {
+		if lang, _ := DetectLanguage(p); lang != "" {
+			current = lang
+		}
+		langs[i] = current
+	}
+	// Leading unclassified paragraphs belong with the first detected language
+	for i := 0; i < len(langs) && langs[i] == ""; i++ {
+		for _, l := range langs[i:] {
+			if l != "" {
+				langs[i] = l
+				break
+			}
+		}
+	}
+
+	builders := make(map[string]*strings.Builder)
+	for i, p := range paragraphs {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		b, ok := builders[langs[i]]
+		if !ok {
+			b = &strings.Builder{}
+			builders[langs[i]] = b
+		}
+		b.WriteString(p)
+		b.WriteString("\n\n")
+	}
+
+	result := make(map[string]string, len(builders))
+	for lang, b := range builders {
+		result[lang] = b.String()
+	}
+	return result
+}
+
+// FilterLanguage keeps only the paragraphs of text detected as lang,
+// dropping foreign-language noise before training
+func FilterLanguage(text, lang string) string {
+	return SplitByLanguage(text)[lang]
+}
+
+// BuildModelsByLanguage splits a mixed-language corpus and trains one model
+// per detected language, each tagged with its language. It stops at the
+// first model that fails to train.
+func BuildModelsByLanguage(text string, cfg MarkovConfig) (map[string]*MarkovModel, error) {
+	models := make(map[string]*MarkovModel)
+	for lang, corpus := range SplitByLanguage(text) {
+		langCfg := cfg
+		langCfg.Language = lang
+		model := NewMarkovModel(langCfg)
+		if err := model.BuildModel(corpus); err != nil {
+			return nil, fmt.Errorf("failed to train %s model: %w", lang, err)
+		}
+		models[lang] = model
+	}
+	return models, nil
+}
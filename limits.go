@@ -0,0 +1,47 @@
+package gophertext
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxWordCount is the largest generation allowed when
+// MarkovConfig.MaxWordCount is zero
+const DefaultMaxWordCount = 100000
+
+// ErrWordCountTooLarge is returned when a generation asks for more words
+// than the model allows. Raise the limit with MarkovConfig.MaxWordCount.
+var ErrWordCountTooLarge = errors.New("word count exceeds the configured maximum")
+
+// checkBatch validates a request for count generations of wordCount words,
+// limiting the batch's total words like a single generation's
+func (m *MarkovModel) checkBatch(count, wordCount int) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if count < 0 {
+		return fmt.Errorf("invalid batch size %d", count)
+	}
+	if err := m.checkWordCount(count); err != nil {
+		return err
+	}
+	if err := m.checkWordCount(wordCount); err != nil {
+		return err
+	}
+	return m.checkWordCount(count * wordCount)
+}
+
+// checkWordCount validates a requested generation length. Zero is allowed
+// and produces no generated words. Callers must hold at least the read lock.
+func (m *MarkovModel) checkWordCount(n int) error {
+	limit := m.config.MaxWordCount
+	if limit <= 0 {
+		limit = DefaultMaxWordCount
+	}
+	switch {
+	case n < 0:
+		return fmt.Errorf("invalid word count %d", n)
+	case n > limit:
+		return fmt.Errorf("%w: %d > %d", ErrWordCountTooLarge, n, limit)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package gophertext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWordCounts(t *testing.T) {
+	corpus := testCorpus(t)
+	const order = 3
+	const prompt = "it was the"
+	// Room for GenerateDiverse's two outputs, which count against one limit
+	raised := 2 * (DefaultMaxWordCount + 1)
+
+	models := make(map[int]*MarkovModel)
+	approx := make(map[int]*ApproxModel)
+	for _, limit := range []int{0, raised} {
+		cfg := MarkovConfig{Order: order, Seed: 1, MaxWordCount: limit}
+		models[limit] = NewMarkovModel(cfg)
+		if err := models[limit].BuildModel(corpus); err != nil {
+			t.Fatal(err)
+		}
+		approx[limit] = NewApproxModel(cfg, ApproxConfig{Buckets: 1 << 12, SketchWidth: 1 << 14})
+		approx[limit].BuildModel(corpus)
+	}
+
+	// Each generator returns its outputs and the words each should have
+	generators := []struct {
+		name string
+		gen  func(limit, n int) ([]string, int, error)
+	}{
+		{"Generate", func(limit, n int) ([]string, int, error) {
+			text, err := models[limit].Generate(n)
+			return []string{text}, prefixWords(n, order), err
+		}},
+		{"GenerateFrom", func(limit, n int) ([]string, int, error) {
+			text, err := models[limit].GenerateFrom(prompt, n)
+			return []string{text}, n + 3, err
+		}},
+		{"GenerateBeam", func(limit, n int) ([]string, int, error) {
+			text, err := models[limit].GenerateBeam(n, 2, nil)
+			return []string{text}, prefixWords(n, order), err
+		}},
+		{"GenerateDiverse", func(limit, n int) ([]string, int, error) {
+			texts, err := models[limit].GenerateDiverse(2, n)
+			return texts, prefixWords(n, order), err
+		}},
+		{"ApproxModel.Generate", func(limit, n int) ([]string, int, error) {
+			text, err := approx[limit].Generate(n)
+			return []string{text}, prefixWords(n, order), err
+		}},
+	}
+
+	for _, g := range generators {
+		for _, tc := range []struct {
+			limit, n int
+			err      error // nil = success; errInvalid for a plain error
+		}{
+			{0, 0, nil},
+			{0, 1, nil},
+			{0, 40, nil},
+			{0, -1, errInvalid},
+			{0, DefaultMaxWordCount + 1, ErrWordCountTooLarge},
+			{raised, DefaultMaxWordCount + 1, nil},
+		} {
+			if g.name == "GenerateBeam" && tc.n > 1000 && tc.err == nil {
+				continue // Beam search over 100,000 words is too slow for a test
+			}
+			t.Run(fmt.Sprintf("%s limit %d count %d", g.name, tc.limit, tc.n), func(t *testing.T) {
+				texts, want, err := g.gen(tc.limit, tc.n)
+				switch {
+				case tc.err == errInvalid:
+					if err == nil || errors.Is(err, ErrWordCountTooLarge) {
+						t.Fatalf("err = %v, want an invalid count error", err)
+					}
+					return
+				case tc.err != nil:
+					if !errors.Is(err, tc.err) {
+						t.Fatalf("err = %v, want %v", err, tc.err)
+					}
+					return
+				case err != nil:
+					t.Fatal(err)
+				}
+				for _, text := range texts {
+					if got := len(strings.Fields(text)); got != want {
+						t.Errorf("generated %d words, want %d", got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+// errInvalid marks test cases expecting a plain invalid-count error
+var errInvalid = errors.New("invalid")
+
+// prefixWords is how many words a generation of n words from a whole
+// prefix of order words returns
+func prefixWords(n, order int) int {
+	if n == 0 {
+		return 0
+	}
+	return max(n, order)
+}
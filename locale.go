@@ -0,0 +1,122 @@
+package gophertext
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// localeProfile holds the post-processing conventions of a language
+type localeProfile struct {
+	openQuote  string // Replaces opening quotation marks
+	closeQuote string // Replaces closing quotation marks
+	punctSpace string // Inserted before ; : ! ? (French typography)
+	stripMarks bool   // Remove diacritics during normalization
+}
+
+var localeProfiles = map[string]localeProfile{
+	"":   {openQuote: "“", closeQuote: "”", stripMarks: true},
+	"en": {openQuote: "“", closeQuote: "”", stripMarks: true},
+	"fr": {openQuote: "« ", closeQuote: " »", punctSpace: " "},
+	"de": {openQuote: "„", closeQuote: "“"},
+	"nl": {openQuote: "„", closeQuote: "”"},
+	"es": {openQuote: "«", closeQuote: "»"},
+	"it": {openQuote: "«", closeQuote: "»"},
+	"pt": {openQuote: "«", closeQuote: "»"},
+	"ru": {openQuote: "«", closeQuote: "»"},
+	"pl": {openQuote: "„", closeQuote: "”"},
+	"sv": {openQuote: "”", closeQuote: "”"},
+	"ja": {openQuote: "「", closeQuote: "」"},
+	"zh": {openQuote: "“", closeQuote: "”"},
+}
+
+// profileFor resolves a BCP-47 tag to its base language profile. Tags without
+// a dedicated profile keep their diacritics and use English quotes.
+func profileFor(tag string) localeProfile {
+	if tag == "" {
+		return localeProfiles[""]
+	}
+	_, base, err := parseLanguage(tag)
+	if err != nil {
+		return localeProfiles[""]
+	}
+	if p, ok := localeProfiles[base]; ok {
+		return p
+	}
+	return localeProfile{openQuote: "“", closeQuote: "”"}
+}
+
+// apply joins words into text following the profile's quote and spacing
+// rules, as adjusted by policy
+func (p localeProfile) apply(words []string, policy OutputPolicy) string {
+	openQuote, closeQuote, punctSpace := p.openQuote, p.closeQuote, p.punctSpace
+	if policy.StraightQuotes {
+		openQuote, closeQuote = `"`, `"`
+	}
+	if policy.NoPunctuationSpace {
+		punctSpace = ""
+	}
+
+	var b strings.Builder
+	prev, closeUp := "", false
+	for i, w := range words {
+		w = policy.ellipsis(w)
+		w, standalone := policy.dash(w)
+		if i > 0 && !closeUp && !(standalone && policy.Dashes == DashEm) {
+			sep := policy.separator(prev, w)
+			if sep == "" && punctSpace != "" && strings.ContainsAny(w[:1], ";:!?") {
+				sep = punctSpace
+			}
+			b.WriteString(sep)
+		}
+		closeUp = standalone && policy.Dashes == DashEm
+		prev = w
+
+		if r, size := utf8.DecodeRuneInString(w); isQuote(r) && size < len(w) {
+			w = openQuote + w[size:]
+		}
+		body := strings.TrimRight(w, ".,;:!?")
+		if r, size := utf8.DecodeLastRuneInString(body); isQuote(r) && size < len(body) {
+			w = body[:len(body)-size] + closeQuote + w[len(body):]
+		}
+		if punctSpace != "" {
+			if r, size := utf8.DecodeLastRuneInString(w); size < len(w) && strings.ContainsRune(";:!?", r) {
+				w = w[:len(w)-size] + punctSpace + string(r)
+			}
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// Language returns the BCP-47 tag the model was trained with
+func (m *MarkovModel) Language() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.Language
+}
+
+// SetLanguage tags the model with a BCP-47 language, changing how generated
+// text is post-processed. Tag models before training so normalization
+// follows the locale as well.
+func (m *MarkovModel) SetLanguage(tag string) error {
+	if tag != "" {
+		canonical, _, err := parseLanguage(tag)
+		if err != nil {
+			return fmt.Errorf("invalid language tag %q: %w", tag, err)
+		}
+		tag = canonical
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Language = tag
+	return nil
+}
+
+func isQuote(r rune) bool {
+	switch r {
+	case '"', '“', '”', '„', '«', '»':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package gophertext
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Sentence markers markovify adds to its chain states
+const (
+	markovifyBegin = "___BEGIN__"
+	markovifyEnd   = "___END__"
+)
+
+// markovifyText is the JSON of a markovify Text model. Chain holds the
+// chain's own JSON, either as a string (Text.to_json) or inline.
+type markovifyText struct {
+	StateSize       int             `json:"state_size"`
+	Chain           json.RawMessage `json:"chain"`
+	ParsedSentences [][]string      `json:"parsed_sentences"`
+}
+
+// LoadMarkovifyJSON converts a model saved by the Python markovify library,
+// either Text.to_json() or Chain.to_json(), into a case-preserving model of
+// the same order. Text models that kept their parsed sentences are
+// retrained from them, so transitions flow across sentence boundaries as
+// they do in gophertext. Otherwise the chain's counts are copied, dropping
+// markovify's begin and end markers; sentence ends then become dead ends
+// that the fallback strategy bridges.
+func LoadMarkovifyJSON(r io.Reader) (*MarkovModel, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read markovify model: %w", err)
+	}
+
+	var text markovifyText
+	chainJSON := json.RawMessage(data)
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return nil, fmt.Errorf("failed to decode markovify model: %w", err)
+		}
+		chainJSON = text.Chain
+		// Text.to_json stores the chain as a JSON string of its own
+		var nested string
+		if json.Unmarshal(chainJSON, &nested) == nil {
+			chainJSON = json.RawMessage(nested)
+		}
+	}
+
+	var states [][2]json.RawMessage
+	if len(chainJSON) > 0 {
+		if err := json.Unmarshal(chainJSON, &states); err != nil {
+			return nil, fmt.Errorf("failed to decode markovify chain: %w", err)
+		}
+	}
+	chain := make(map[string]map[string]int)
+	order := text.StateSize
+	for _, entry := range states {
+		var state []string
+		var next map[string]int
+		if err := json.Unmarshal(entry[0], &state); err != nil {
+			return nil, fmt.Errorf("failed to decode markovify state: %w", err)
+		}
+		if err := json.Unmarshal(entry[1], &next); err != nil {
+			return nil, fmt.Errorf("failed to decode markovify transitions: %w", err)
+		}
+		if order == 0 {
+			order = len(state)
+		}
+		if len(state) != order {
+			return nil, fmt.Errorf("markovify state %q does not have %d words", state, order)
+		}
+		if containsWord(state, markovifyBegin) {
+			continue
+		}
+		prefix := joinKey(state)
+		for word, n := range next {
+			if word == markovifyEnd || !validToken(word) {
+				continue
+			}
+			if n > 0 {
+				addTransition(chain, prefix, word, n)
+			}
+		}
+	}
+	if order < 1 {
+		return nil, fmt.Errorf("markovify model has no state size")
+	}
+
+	model := NewMarkovModel(MarkovConfig{Order: order, PreserveCase: true})
+	if len(text.ParsedSentences) > 0 {
+		sentences := make([]string, len(text.ParsedSentences))
+		for i, words := range text.ParsedSentences {
+			sentences[i] = strings.Join(words, " ")
+		}
+		if err := model.BuildModel(strings.Join(sentences, " ")); err != nil {
+			return nil, err
+		}
+		return model, nil
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("markovify model has no transitions")
+	}
+	if err := model.restore(modelMeta{Config: model.config}, chain); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// SaveMarkovifyJSON writes the model in the JSON of a markovify Text, for
+// use from Python with markovify.Text.from_json. Markovify chains are made
+// of sentences, so every transition out of a sentence end becomes an end
+// marker plus a sentence start, and states reaching back past a sentence
+// end are padded with begin markers. Parsed sentences aren't included, so
+// markovify skips its overlap test on the output.
+func (m *MarkovModel) SaveMarkovifyJSON(w io.Writer) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	order := m.config.Order
+
+	states := make(map[string]map[string]int)
+	keys := make(map[string][]string)
+	add := func(state []string, word string, n int) {
+		key := joinKey(state)
+		if states[key] == nil {
+			states[key] = make(map[string]int)
+			keys[key] = state
+		}
+		states[key][word] += n
+	}
+	begin := make([]string, order)
+	for i := range begin {
+		begin[i] = markovifyBegin
+	}
+
+	for prefix, suffixes := range m.savedChain() {
+		words := splitKey(prefix)
+		// Words before the last sentence end inside the prefix belong to
+		// an earlier sentence
+		start := 0
+		for i := 0; i < order-1; i++ {
+			if m.splitter.IsTerminal(words[i]) {
+				start = i + 1
+			}
+		}
+		state := append(append([]string(nil), begin[:start]...), words[start:]...)
+		ended := m.splitter.IsTerminal(words[order-1])
+		for word, n := range suffixes {
+			if ended {
+				add(state, markovifyEnd, n)
+				add(begin, word, n)
+			} else {
+				add(state, word, n)
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(states))
+	for key := range states {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	entries := make([][2]any, len(sorted))
+	for i, key := range sorted {
+		entries[i] = [2]any{keys[key], states[key]}
+	}
+	chain, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode markovify chain: %w", err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	err = enc.Encode(struct {
+		StateSize       int        `json:"state_size"`
+		Chain           string     `json:"chain"`
+		ParsedSentences [][]string `json:"parsed_sentences"`
+	}{order, string(chain), nil})
+	if err != nil {
+		return fmt.Errorf("failed to encode markovify model: %w", err)
+	}
+	return nil
+}
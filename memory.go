@@ -0,0 +1,56 @@
+package gophertext
+
+// Rough per-entry costs used to estimate chain memory
+const (
+	prefixOverhead = 240 // Outer map bucket share, key header and an empty suffix map
+	suffixBytes    = 40  // Suffix map bucket share: string header and count
+)
+
+// estimateChainBytes approximates the heap used by a chain. Suffix strings
+// are counted in full although training shares them with the corpus.
+func estimateChainBytes(chain map[string]map[string]int) int64 {
+	var total int64
+	for k, v := range chain {
+		total += prefixOverhead + int64(len(k))
+		for s := range v {
+			total += suffixBytes + int64(len(s))
+		}
+	}
+	return total
+}
+
+// newEvictionSketch sizes the frequency sketch to a small share of the budget
+func newEvictionSketch(budget int64) *countMinSketch {
+	width := budget / 64
+	if width < 1<<12 {
+		width = 1 << 12
+	}
+	if width > 1<<22 {
+		width = 1 << 22
+	}
+	return newCountMinSketch(int(width), 4)
+}
+
+// evictRare drops the rarest transitions until the chain fits in 90% of
+// MaxMemoryBytes. Rarity comes from the sketch, which remembers counts of
+// transitions that were evicted earlier, so recurring phrases can win their
+// place back. Callers must hold the write lock.
+func (m *MarkovModel) evictRare() {
+	target := m.config.MaxMemoryBytes / 10 * 9
+	for threshold := uint32(1); m.chainBytes > target; threshold *= 2 {
+		for prefix, suffixes := range m.chain {
+			for s := range suffixes {
+				if m.sketch.estimate(prefix, s) <= threshold {
+					delete(suffixes, s)
+				}
+			}
+			if len(suffixes) == 0 {
+				delete(m.chain, prefix)
+			}
+		}
+		m.chainBytes = estimateChainBytes(m.chain)
+		if threshold >= 1<<31 {
+			break
+		}
+	}
+}
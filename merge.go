@@ -0,0 +1,122 @@
+package gophertext
+
+import (
+	"fmt"
+	"io"
+	"maps"
+)
+
+// Merge adds every transition of other to m. Merging sums transition
+// counts, so it is commutative and associative: shards trained on separate
+// machines can be combined in any order with the same result.
+func (m *MarkovModel) Merge(other *MarkovModel) error {
+	if m == other {
+		return fmt.Errorf("cannot merge a model into itself")
+	}
+	if err := compatibleConfigs(m.settings(), other.settings()); err != nil {
+		return err
+	}
+
+	// Copy other first instead of holding both locks, so a.Merge(b) and
+	// b.Merge(a) running together can't deadlock
+	other.mu.RLock()
+	chain := cloneChain(other.chain)
+	ngrams := maps.Clone(other.ngrams)
+	casing := maps.Clone(other.casing)
+	negative := cloneChain(other.negative)
+	other.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for prefix, suffixes := range chain {
+		m.addSuffixes(prefix, suffixes)
+	}
+	m.invalidateIndex()
+	for h := range ngrams {
+		if m.ngrams == nil {
+			m.ngrams = make(map[uint64]bool)
+		}
+		m.ngrams[h] = true
+	}
+	if len(casing) > 0 {
+		m.addCasing(casing)
+	}
+	for prefix, suffixes := range negative {
+		if m.negative == nil {
+			m.negative = make(map[string]map[string]int)
+		}
+		if m.negative[prefix] == nil {
+			m.negative[prefix] = make(map[string]int)
+		}
+		for word, n := range suffixes {
+			m.negative[prefix][word] += n
+		}
+	}
+	return nil
+}
+
+// MergeAll loads the serialized partial models produced by workers and
+// combines them into one model. The first model's configuration wins; the
+// rest must agree on everything that affects tokenization.
+func MergeAll(readers []io.Reader) (*MarkovModel, error) {
+	if len(readers) == 0 {
+		return nil, fmt.Errorf("no models to merge")
+	}
+
+	var merged *MarkovModel
+	for i, r := range readers {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read model %d: %w", i, err)
+		}
+		part := NewMarkovModel(MarkovConfig{})
+		if err := part.Load(data); err != nil {
+			return nil, fmt.Errorf("failed to load model %d: %w", i, err)
+		}
+
+		if merged == nil {
+			merged = part
+			continue
+		}
+		if err := merged.Merge(part); err != nil {
+			return nil, fmt.Errorf("model %d: %w", i, err)
+		}
+	}
+	return merged, nil
+}
+
+// compatibleConfigs checks that two models tokenized their corpora the same way
+// cloneChain copies a chain deeply enough that training either copy leaves
+// the other unchanged
+func cloneChain(chain map[string]map[string]int) map[string]map[string]int {
+	clone := make(map[string]map[string]int, len(chain))
+	for prefix, suffixes := range chain {
+		clone[prefix] = maps.Clone(suffixes)
+	}
+	return clone
+}
+
+func compatibleConfigs(a, b MarkovConfig) error {
+	switch {
+	case a.Order != b.Order:
+		return fmt.Errorf("order mismatch: %d vs %d", a.Order, b.Order)
+	case a.PreserveCase != b.PreserveCase:
+		return fmt.Errorf("case handling mismatch")
+	case a.EntityMode != b.EntityMode:
+		return fmt.Errorf("entity mode mismatch")
+	case a.Placeholders.Enabled != b.Placeholders.Enabled:
+		return fmt.Errorf("placeholder mode mismatch")
+	case a.Language != "" && b.Language != "" && a.Language != b.Language:
+		return fmt.Errorf("language mismatch: %s vs %s", a.Language, b.Language)
+	case a.MaxVocabulary != b.MaxVocabulary:
+		return fmt.Errorf("vocabulary cap mismatch: %d vs %d", a.MaxVocabulary, b.MaxVocabulary)
+	case a.MinTokenLen != b.MinTokenLen || a.MaxTokenLen != b.MaxTokenLen:
+		return fmt.Errorf("token length limit mismatch")
+	case a.Hyphens != b.Hyphens:
+		return fmt.Errorf("hyphen policy mismatch")
+	case a.NoveltyN != b.NoveltyN:
+		return fmt.Errorf("novelty n-gram length mismatch: %d vs %d", a.NoveltyN, b.NoveltyN)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package mobile is a gomobile-bindable wrapper around gophertext for iOS
+// and Android apps. It only uses types gomobile can bind: strings, numbers,
+// byte slices, errors and pointers to exported structs.
+//
+//	gomobile bind -target=android github.com/jasonlovesdoggo/gophertext/mobile
+package mobile
+
+import (
+	"github.com/jasonlovesdoggo/gophertext"
+)
+
+// Config is the bindable subset of gophertext.MarkovConfig
+type Config struct {
+	Order          int
+	MaxRepeat      int
+	MinSentenceLen int
+	MaxSentenceLen int
+	ParagraphBreak int
+	Language       string
+}
+
+// NewConfig returns the settings used by the example model
+func NewConfig() *Config {
+	return &Config{
+		Order:          3,
+		MaxRepeat:      2,
+		MinSentenceLen: 5,
+		MaxSentenceLen: 25,
+		ParagraphBreak: 5,
+	}
+}
+
+// Model is a Markov text generator
+type Model struct {
+	model *gophertext.MarkovModel
+}
+
+// NewModel creates an untrained model
+func NewModel(cfg *Config) (*Model, error) {
+	if cfg == nil {
+		cfg = NewConfig()
+	}
+	m := gophertext.NewMarkovModel(gophertext.MarkovConfig{
+		Order:          cfg.Order,
+		MaxRepeat:      cfg.MaxRepeat,
+		MinSentenceLen: cfg.MinSentenceLen,
+		MaxSentenceLen: cfg.MaxSentenceLen,
+		ParagraphBreak: cfg.ParagraphBreak,
+	})
+	if err := m.SetLanguage(cfg.Language); err != nil {
+		return nil, err
+	}
+	return &Model{model: m}, nil
+}
+
+// LoadModel restores a model saved by gophertext, such as one bundled as an
+// app asset
+func LoadModel(data []byte) (*Model, error) {
+	m := gophertext.NewMarkovModel(gophertext.MarkovConfig{})
+	if err := m.Load(data); err != nil {
+		return nil, err
+	}
+	return &Model{model: m}, nil
+}
+
+// Train adds text to the model
+func (m *Model) Train(text string) error {
+	return m.model.BuildModel(text)
+}
+
+// Generate produces about words words of text
+func (m *Model) Generate(words int) (string, error) {
+	return m.model.Generate(words)
+}
+
+// GenerateFrom continues prompt with words generated words
+func (m *Model) GenerateFrom(prompt string, words int) (string, error) {
+	return m.model.GenerateFrom(prompt, words)
+}
+
+// Save serializes the model
+func (m *Model) Save() ([]byte, error) {
+	return m.model.Save()
+}
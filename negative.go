@@ -0,0 +1,29 @@
+package gophertext
+
+// TrainNegative records the transitions of text as ones the model should not
+// produce, such as boilerplate disclaimers or spam phrases, without editing
+// the positive corpus. Each observation removes NegativeWeight from the
+// transition's sampling weight; with the default NegativeWeight of zero the
+// transitions are suppressed entirely. Suppressions persist with the model.
+func (m *MarkovModel) TrainNegative(text string) {
+	words := m.tokenize(text)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Order == OrderAuto {
+		m.config.Order = defaultOrder
+	}
+	order := m.config.Order
+	if m.negative == nil {
+		m.negative = make(map[string]map[string]int)
+	}
+	for i := 0; i+order < len(words); i++ {
+		prefix := joinKey(words[i : i+order])
+		suffixes := m.negative[prefix]
+		if suffixes == nil {
+			suffixes = make(map[string]int)
+			m.negative[prefix] = suffixes
+		}
+		suffixes[words[i+order]]++
+	}
+}
@@ -0,0 +1,167 @@
+package gophertext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// arpaScale converts ARPA probabilities to pseudo-counts: a probability p
+// becomes round(p * arpaScale) occurrences
+const arpaScale = 1000
+
+// ExportCounts writes the chain as an n-gram count file in the format of
+// SRILM's ngram-count -write and KenLM's count tools: one (Order+1)-gram
+// per line, its words separated by spaces, then a tab and its count.
+// Whitespace and backslashes inside a word are escaped so each word stays
+// one field; ImportCounts decodes them.
+// Lines are sorted, so exports of the same model are identical. Privacy
+// settings apply as they do for Save.
+func (m *MarkovModel) ExportCounts(w io.Writer) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	chain := m.savedChain()
+
+	lines := make([]string, 0, len(chain))
+	for prefix, suffixes := range chain {
+		words := splitKey(prefix)
+		fields := make([]string, len(words)+1)
+		for i, w := range words {
+			fields[i] = escapeCountWord(w)
+		}
+		for word, n := range suffixes {
+			fields[len(words)] = escapeCountWord(word)
+			lines = append(lines, strings.Join(fields, " ")+"\t"+strconv.Itoa(n))
+		}
+	}
+	sort.Strings(lines)
+
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		bw.WriteString(line)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// ImportCounts adds the n-grams of an SRILM or KenLM style count file to
+// the chain. Each line holds an n-gram and its count separated by
+// whitespace; only (Order+1)-grams are used, so files listing every order
+// can be read as they are. N-grams with the sentence markers <s> and </s>
+// are skipped and <unk> becomes UnknownToken. Tokens are taken as they
+// appear, so they should match the model's normalization.
+func (m *MarkovModel) ImportCounts(r io.Reader) error {
+	return m.importNgrams(r, func(fields []string) ([]string, int, bool, error) {
+		if len(fields) < 2 {
+			return nil, 0, false, nil
+		}
+		count, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil || count < 0 {
+			return nil, 0, false, fmt.Errorf("invalid count %q", fields[len(fields)-1])
+		}
+		return fields[:len(fields)-1], count, true, nil
+	})
+}
+
+// ImportARPA adds the (Order+1)-grams of an ARPA language model, as written
+// by SRILM or KenLM, to the chain. ARPA files hold log10 probabilities
+// rather than counts, so each probability p becomes round(p*1000)
+// pseudo-occurrences; backoff weights and other orders are ignored.
+func (m *MarkovModel) ImportARPA(r io.Reader) error {
+	n := m.settings().Order + 1
+	section := 0
+	return m.importNgrams(r, func(fields []string) ([]string, int, bool, error) {
+		if len(fields) == 1 && strings.HasPrefix(fields[0], `\`) {
+			section = 0
+			if strings.HasSuffix(fields[0], "-grams:") {
+				section, _ = strconv.Atoi(strings.TrimSuffix(fields[0][1:], "-grams:"))
+			}
+			return nil, 0, false, nil
+		}
+		if section != n || len(fields) < n+1 {
+			return nil, 0, false, nil
+		}
+		logProb, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("invalid log probability %q", fields[0])
+		}
+		count := int(math.Round(math.Pow(10, logProb) * arpaScale))
+		return fields[1 : n+1], count, true, nil
+	})
+}
+
+// importNgrams reads n-gram lines with parse, which returns the words and
+// count of a line or false to skip it, and merges the (Order+1)-grams into
+// the chain. Nothing is merged if any line fails to parse.
+func (m *MarkovModel) importNgrams(r io.Reader, parse func(fields []string) ([]string, int, bool, error)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Order == OrderAuto {
+		return fmt.Errorf("n-gram import needs a fixed order, not OrderAuto")
+	}
+	order := m.config.Order
+
+	local := make(map[string]map[string]int)
+	imported := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		gram, count, ok, err := parse(fields)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if !ok || len(gram) != order+1 || count == 0 {
+			continue
+		}
+		words, ok := importWords(gram)
+		if !ok {
+			continue
+		}
+		addTransition(local, joinKey(words[:order]), words[order], count)
+		imported++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read n-grams: %w", err)
+	}
+	if imported == 0 {
+		return fmt.Errorf("no %d-grams found for an order %d model", order+1, order)
+	}
+	for prefix, suffixes := range local {
+		m.addSuffixes(prefix, suffixes)
+	}
+	if !m.config.Deterministic {
+		m.updated = time.Now()
+	}
+	m.invalidateIndex()
+	return nil
+}
+
+// importWords decodes the words of an imported n-gram, mapping <unk> to
+// UnknownToken. It reports false for n-grams with sentence markers.
+func importWords(gram []string) ([]string, bool) {
+	words := make([]string, len(gram))
+	for i, field := range gram {
+		words[i] = unescapeCountWord(field)
+	}
+	for i, w := range words {
+		switch w {
+		case "<s>", "</s>":
+			return nil, false
+		case "<unk>":
+			words[i] = UnknownToken
+		}
+		if !validToken(words[i]) {
+			return nil, false
+		}
+	}
+	return words, true
+}
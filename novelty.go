@@ -0,0 +1,91 @@
+package gophertext
+
+import (
+	"hash/fnv"
+)
+
+// Novelty measures how much of a generated text was copied verbatim from
+// the training corpus
+type Novelty struct {
+	N            int     // N-gram length compared
+	NGrams       int     // N-grams in the generated text
+	Verbatim     int     // N-grams that also occur in the corpus
+	Overlap      float64 // Verbatim / NGrams
+	LongestMatch int     // Longest run of words copied verbatim from the corpus
+}
+
+// NoveltyReport measures what fraction of generated's n-grams appear
+// verbatim in the training data. Models trained with NoveltyN set compare
+// NoveltyN-grams against a digest of the corpus. Otherwise the chain
+// itself is used, which only knows (Order+1)-grams.
+func (m *MarkovModel) NoveltyReport(generated string) Novelty {
+	words := m.tokenize(generated)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	n := m.config.Order + 1
+	seen := m.chainContains
+	if len(m.ngrams) > 0 {
+		n = m.config.NoveltyN
+		seen = func(gram []string) bool {
+			return m.ngrams[hashNgram(gram)]
+		}
+	}
+
+	report := Novelty{N: n}
+	run := 0
+	for i := 0; i+n <= len(words); i++ {
+		report.NGrams++
+		if !seen(words[i : i+n]) {
+			run = 0
+			continue
+		}
+		report.Verbatim++
+		// Overlapping matches extend the copied run by one word each
+		if run == 0 {
+			run = n
+		} else {
+			run++
+		}
+		if run > report.LongestMatch {
+			report.LongestMatch = run
+		}
+	}
+	if report.NGrams > 0 {
+		report.Overlap = float64(report.Verbatim) / float64(report.NGrams)
+	}
+	return report
+}
+
+// chainContains reports whether the (Order+1)-gram is a trained transition
+func (m *MarkovModel) chainContains(gram []string) bool {
+	prefix := joinKey(gram[:len(gram)-1])
+	return m.chain[prefix][gram[len(gram)-1]] > 0
+}
+
+// recordNgrams adds the NoveltyN-grams starting in [from, to) to the corpus
+// digest
+func (m *MarkovModel) recordNgrams(words []string, from, to int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := m.config.NoveltyN
+	if n <= 0 {
+		return
+	}
+	if m.ngrams == nil {
+		m.ngrams = make(map[uint64]bool)
+	}
+	for i := from; i < to && i+n <= len(words); i++ {
+		m.ngrams[hashNgram(words[i:i+n])] = true
+	}
+}
+
+func hashNgram(gram []string) uint64 {
+	h := fnv.New64a()
+	for _, w := range gram {
+		h.Write([]byte(w))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
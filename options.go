@@ -0,0 +1,101 @@
+package gophertext
+
+// GenerateOption customizes a single call to Generate
+type GenerateOption func(*generateOptions)
+
+type generateOptions struct {
+	entities map[string][]string // Entity token -> replacement names
+	fallback FallbackStrategy    // Recovery from dead ends
+	debug    bool                // Record a TraceStep per generated word
+	seed     []string            // Normalized prompt tokens to continue from
+	prompt   []string            // Prompt tokens as printed, before MaxVocabulary maps any to UnknownToken
+	sampler  Sampler             // Next-word selection (nil = weighted)
+
+	temperature float64 // Exponent 1/temperature applied to weights (0 = unchanged)
+	topK        int     // Candidates kept per step, heaviest first (0 = all)
+	topP        float64 // Probability mass kept per step, heaviest first (0 = all)
+
+	constraints    ConstraintSet // Words the output may not contain
+	constraintName string        // Named ConstraintSet stored on the model
+
+	excludeWords    []string    // Excluded words as given
+	excludePrefixes []string    // Excluded phrases as given
+	exclusions      *exclusions // Exclusions normalized by generate
+
+	anchorWords    []string        // Anchor words as given
+	anchors        map[string]bool // Anchor words normalized by generate
+	anchorStrength float64         // Extra weight given to anchor transitions
+
+	boostWords map[string]float64 // Boosted words as given
+	boosts     map[string]float64 // Boosted words normalized by generate
+
+	synonyms    Synonyms // Replacements for generated words
+	synonymRate float64  // Probability a word with synonyms is replaced
+
+	diversity *diversity // Transitions to avoid, set by GenerateDiverse
+	cacheKey  string     // OutputCache grouping set by WithCacheKey
+
+	person   string          // Person of the current sentence's first pronoun, for PronounConsistency
+	atStart  bool            // The next word starts a sentence
+	starters map[string]bool // ForbiddenStarters normalized by generate
+	enders   map[string]bool // ForbiddenEnders normalized by generate
+
+	ending    float64 // EndingBias in effect for the next word, 0 away from the requested length
+	remaining int     // Words still to generate, while ending is set
+}
+
+func newGenerateOptions(opts []GenerateOption) *generateOptions {
+	o := &generateOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithEntities substitutes entity placeholders of kind (EntityPerson,
+// EntityPlace) with names picked from the list. Requires a model trained
+// with EntityMode.
+func WithEntities(kind string, names ...string) GenerateOption {
+	return func(o *generateOptions) {
+		if o.entities == nil {
+			o.entities = make(map[string][]string)
+		}
+		o.entities[kind] = append(o.entities[kind], names...)
+	}
+}
+
+// WithAnchorWords keeps a generation loosely about a topic by boosting every
+// transition into one of words: its sampling weight is multiplied by
+// 1+strength. Anchors are normalized like training text.
+func WithAnchorWords(words []string, strength float64) GenerateOption {
+	return func(o *generateOptions) {
+		if strength <= 0 {
+			return
+		}
+		o.anchorWords = append(o.anchorWords, words...)
+		o.anchorStrength = strength
+	}
+}
+
+// WithBoost multiplies the sampling weight of each word in boosts by its
+// factor for one generation, so output can favor "gopher" today and
+// "ferret" tomorrow without retraining. Factors below 1 make a word rarer
+// and 0 removes it. Words are normalized like training text.
+func WithBoost(boosts map[string]float64) GenerateOption {
+	return func(o *generateOptions) {
+		if o.boostWords == nil {
+			o.boostWords = make(map[string]float64)
+		}
+		for w, factor := range boosts {
+			if factor >= 0 {
+				o.boostWords[w] = factor
+			}
+		}
+	}
+}
+
+// allows reports whether word may follow tokens under the constraint set and
+// exclusions of o
+func (o *generateOptions) allows(tokens []string, word string) bool {
+	return o.constraints.Allows(word) && o.exclusions.allows(tokens, word)
+}
@@ -0,0 +1,73 @@
+package gophertext
+
+// defaultOrder is used when no order is configured
+const defaultOrder = 2
+
+// OrderAuto makes the first BuildModel call pick the chain order from the
+// corpus with RecommendOrder, or the heuristic given with
+// WithOrderHeuristic. Config reports the chosen order afterwards.
+// Training that can't see its corpus up front (TrainExternal,
+// NewStreamTrainer, NewApproxModel, or TrainNegative before any
+// BuildModel) uses order 2.
+const OrderAuto = -1
+
+// OrderHeuristic picks the order for a model configured with OrderAuto
+// from the token count and vocabulary size of its first training corpus
+type OrderHeuristic func(tokens, vocabulary int) int
+
+// WithOrderHeuristic makes a BuildModel call that resolves OrderAuto pick
+// the order with h instead of RecommendOrder
+func WithOrderHeuristic(h OrderHeuristic) TrainOption {
+	return func(o *trainOptions) {
+		o.orderHeuristic = h
+	}
+}
+
+// pickOrder applies the heuristic of o, or RecommendOrder, to words
+func pickOrder(words []string, o *trainOptions) int {
+	vocabulary := make(map[string]bool)
+	for _, w := range words {
+		vocabulary[w] = true
+	}
+	h := OrderHeuristic(RecommendOrder)
+	if o != nil && o.orderHeuristic != nil {
+		h = o.orderHeuristic
+	}
+	return max(1, h(len(words), len(vocabulary)))
+}
+
+// RecommendOrder suggests a chain order for a corpus. Each order needs
+// roughly ten times more text than the one below it before its prefixes
+// repeat often enough to offer a choice of continuations: order 1 below
+// 20,000 tokens, 2 below 200,000, 3 below 2,000,000 and 4 beyond. A corpus
+// where more than a quarter of the tokens are distinct words drops one
+// order, since its prefixes repeat even less. Too high an order for the
+// corpus makes generation regurgitate the training text verbatim.
+func RecommendOrder(tokens, vocabulary int) int {
+	order := 4
+	switch {
+	case tokens < 20000:
+		order = 1
+	case tokens < 200000:
+		order = 2
+	case tokens < 2000000:
+		order = 3
+	}
+	if order > 1 && vocabulary*4 > tokens {
+		order--
+	}
+	return order
+}
+
+// resolveOrder replaces OrderAuto with the order picked for words
+func (m *MarkovModel) resolveOrder(words []string, o *trainOptions) {
+	if m.settings().Order != OrderAuto {
+		return
+	}
+	order := pickOrder(words, o)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Order == OrderAuto {
+		m.config.Order = order
+	}
+}
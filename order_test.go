@@ -0,0 +1,40 @@
+package gophertext
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWithOrderHeuristic(t *testing.T) {
+	corpus := testCorpus(t)
+	var wg sync.WaitGroup
+	for _, want := range []int{1, 3, 5} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m := NewMarkovModel(MarkovConfig{Order: OrderAuto})
+			h := func(tokens, vocabulary int) int { return want }
+			if err := m.BuildModel(corpus, WithOrderHeuristic(h)); err != nil {
+				t.Error(err)
+				return
+			}
+			if got := m.Config().Order; got != want {
+				t.Errorf("order = %d, want %d", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+
+	m := NewMarkovModel(MarkovConfig{Order: OrderAuto})
+	if err := m.BuildModel(corpus); err != nil {
+		t.Fatal(err)
+	}
+	words := m.trainingTokens(corpus)
+	vocabulary := make(map[string]bool)
+	for _, w := range words {
+		vocabulary[w] = true
+	}
+	if got, want := m.Config().Order, RecommendOrder(len(words), len(vocabulary)); got != want {
+		t.Errorf("default order = %d, want RecommendOrder's %d", got, want)
+	}
+}
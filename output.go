@@ -0,0 +1,104 @@
+package gophertext
+
+import "strings"
+
+// OutputPolicy controls whitespace and punctuation in generated text. The
+// zero value keeps the corpus punctuation and the language's quote and
+// spacing conventions.
+type OutputPolicy struct {
+	StraightQuotes     bool          // Write " instead of the language's curly quotes
+	Ellipsis           EllipsisStyle // How runs of three dots are written
+	Dashes             DashStyle     // How dashes between words are written
+	SentenceSpacing    int           // Spaces after a sentence end (0 or 1 = one)
+	AttachPunctuation  bool          // Join stray punctuation tokens ("," or "!") to the previous word
+	NoPunctuationSpace bool          // Drop the space French typography puts before ; : ! ?
+}
+
+// EllipsisStyle selects how ellipses are written
+type EllipsisStyle int
+
+const (
+	EllipsisKeep    EllipsisStyle = iota // As in the corpus
+	EllipsisUnicode                      // A single "…" character
+	EllipsisDots                         // Three periods "..."
+)
+
+// DashStyle selects how dashes between words are written. Hyphens inside
+// words are never changed.
+type DashStyle int
+
+const (
+	DashKeep     DashStyle = iota // As in the corpus
+	DashEm                        // Closed em dash: "word—word"
+	DashSpacedEn                  // Spaced en dash: "word – word"
+	DashDouble                    // Spaced double hyphen: "word -- word"
+)
+
+// dashForms are the spellings of a dash between words
+var dashForms = []string{"---", "--", "—", "–"}
+
+// ellipsis rewrites the ellipses in word
+func (p OutputPolicy) ellipsis(word string) string {
+	switch p.Ellipsis {
+	case EllipsisUnicode:
+		return strings.ReplaceAll(word, "...", "…")
+	case EllipsisDots:
+		return strings.ReplaceAll(word, "…", "...")
+	}
+	return word
+}
+
+// dash rewrites the dashes in word. A word that is only a dash is returned
+// as the bare dash with standalone set, so the caller can fix its spacing.
+func (p OutputPolicy) dash(word string) (string, bool) {
+	if p.Dashes == DashKeep {
+		return word, false
+	}
+	standalone := word == "-"
+	for _, form := range dashForms {
+		if word == form {
+			standalone = true
+		}
+	}
+
+	var dash string
+	switch p.Dashes {
+	case DashEm:
+		dash = "—"
+	case DashSpacedEn:
+		dash = "–"
+	case DashDouble:
+		dash = "--"
+	}
+	if standalone {
+		return dash, true
+	}
+	if !strings.ContainsAny(word, "—–") && !strings.Contains(word, "--") {
+		return word, false
+	}
+	if p.Dashes != DashEm {
+		dash = " " + dash + " "
+	}
+	pairs := make([]string, 0, 2*len(dashForms))
+	for _, form := range dashForms {
+		pairs = append(pairs, form, dash)
+	}
+	return strings.NewReplacer(pairs...).Replace(word), false
+}
+
+// isPunctuationToken reports whether word is nothing but punctuation that
+// attaches to the word before it
+func isPunctuationToken(word string) bool {
+	return word != "" && strings.Trim(word, ".,;:!?…)") == ""
+}
+
+// separator returns the whitespace to put before word
+func (p OutputPolicy) separator(prev, word string) string {
+	switch {
+	case p.AttachPunctuation && isPunctuationToken(word):
+		return ""
+	case p.SentenceSpacing > 1 && endsSentence(prev):
+		return strings.Repeat(" ", p.SentenceSpacing)
+	}
+	return " "
+}
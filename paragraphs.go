@@ -0,0 +1,50 @@
+package gophertext
+
+import "strings"
+
+// paragraphSizes plans how many of n sentences go in each paragraph. Sizes
+// vary at random around ParagraphBreak, from half of it up to half again
+// as many, so paragraphs don't all look alike. A lone sentence left for the
+// last paragraph joins the one before, unless ParagraphBreak asks for
+// one-sentence paragraphs.
+func (m *MarkovModel) paragraphSizes(n int) []int {
+	mean := m.config.ParagraphBreak
+	lo, hi := max(1, (mean+1)/2), mean+mean/2
+	var sizes []int
+	for left := n; left > 0; {
+		size := min(left, lo+m.rng.Intn(hi-lo+1))
+		sizes = append(sizes, size)
+		left -= size
+	}
+	if last := len(sizes) - 1; mean > 1 && last > 0 && sizes[last] == 1 {
+		sizes[last-1]++
+		sizes = sizes[:last]
+	}
+	return sizes
+}
+
+// layParagraphs formats words as paragraphs of whole sentences separated
+// by blank lines. Words after the last sentence end stay with the last
+// paragraph.
+func (m *MarkovModel) layParagraphs(words []string, format func([]string) string) string {
+	var ends []int // Index just past each sentence
+	for i, w := range words {
+		if m.splitter.IsTerminal(w) {
+			ends = append(ends, i+1)
+		}
+	}
+	if len(ends) == 0 {
+		ends = append(ends, len(words))
+	}
+	ends[len(ends)-1] = len(words)
+
+	var paragraphs []string
+	start, sentences := 0, 0
+	for _, size := range m.paragraphSizes(len(ends)) {
+		sentences += size
+		end := ends[sentences-1]
+		paragraphs = append(paragraphs, format(words[start:end]))
+		start = end
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
@@ -0,0 +1,44 @@
+package gophertext
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// LoadTopN loads only the n most frequent prefixes of a saved model, for
+// environments where a fast start matters more than the rare tail of the
+// chain. Models saved in the current format store prefixes by frequency,
+// so only the needed part is decoded; older files are loaded in full and
+// then trimmed. Trimming creates dead ends, which Compact can clean up.
+func (m *MarkovModel) LoadTopN(data []byte, n int) error {
+	if n < 1 {
+		return fmt.Errorf("LoadTopN needs n >= 1, got %d", n)
+	}
+	var meta modelMeta
+	var chain map[string]map[string]int
+	var sorted bool
+	var err error
+	if bytes.HasPrefix(data, []byte(formatMagic)) {
+		meta, chain, sorted, err = decodeModel(data, n)
+	} else {
+		meta, chain, err = decodeGob(data)
+	}
+	if err != nil {
+		return err
+	}
+	if !sorted {
+		keepTopPrefixes(chain, n)
+	}
+	return m.restore(meta, chain)
+}
+
+// keepTopPrefixes drops all but the n prefixes with the most occurrences
+func keepTopPrefixes(chain map[string]map[string]int, n int) {
+	if len(chain) <= n {
+		return
+	}
+	prefixes, _ := prefixesByFrequency(chain)
+	for _, prefix := range prefixes[n:] {
+		delete(chain, prefix)
+	}
+}
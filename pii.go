@@ -0,0 +1,62 @@
+package gophertext
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`(?:\+[0-9]{1,3}[ .\-]?)?\(?[0-9]{2,4}\)?[ .\-]?[0-9]{3,4}[ .\-]?[0-9]{3,4}`)
+	ssnPattern   = regexp.MustCompile(`\b[0-9]{3}-[0-9]{2}-[0-9]{4}\b`)
+	cardPattern  = regexp.MustCompile(`\b(?:[0-9][ \-]?){12,18}[0-9]\b`)
+)
+
+// piiKind classifies a single token that looks like personal data, returning
+// "" for ordinary words
+func piiKind(word string) string {
+	word = strings.Trim(word, ".,;:!?()[]\"'“”‘’")
+	switch {
+	case emailPattern.MatchString(word) && emailPattern.FindString(word) == word:
+		return "email"
+	case ssnPattern.FindString(word) == word && word != "":
+		return "ssn"
+	case cardPattern.FindString(word) == word && word != "" && luhnValid(word):
+		return "card"
+	case phonePattern.FindString(word) == word && word != "" && countDigits(word) >= 7:
+		return "phone"
+	}
+	return ""
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// luhnValid runs the Luhn checksum over the digits of s
+func luhnValid(s string) bool {
+	sum, double, digits := 0, false, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
@@ -0,0 +1,130 @@
+package gophertext
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Typed placeholder tokens substituted for numeric values during training
+const (
+	PlaceholderNumber  = "<NUM>"
+	PlaceholderYear    = "<YEAR>"
+	PlaceholderDate    = "<DATE>"
+	PlaceholderMoney   = "<MONEY>"
+	PlaceholderPercent = "<PCT>"
+)
+
+// PlaceholderConfig controls number and date placeholders. When enabled,
+// training replaces numeric tokens with typed placeholders and generation
+// re-synthesizes fresh values within the configured ranges.
+type PlaceholderConfig struct {
+	Enabled        bool
+	NumberMin      int     // Smallest synthesized <NUM> (default 1)
+	NumberMax      int     // Largest synthesized <NUM> (default 1000)
+	YearMin        int     // Earliest synthesized <YEAR> (default 1900)
+	YearMax        int     // Latest synthesized <YEAR> (default 2030)
+	DateLayout     string  // time layout for <DATE> (default "2006-01-02")
+	CurrencySymbol string  // Prefix for <MONEY> (default "$")
+	MoneyMax       float64 // Largest synthesized <MONEY> (default 1000)
+}
+
+var (
+	yearPattern    = regexp.MustCompile(`^1[0-9]{3}$|^20[0-9]{2}$`)
+	numberPattern  = regexp.MustCompile(`^[0-9]{1,3}(,[0-9]{3})+(\.[0-9]+)?$|^[0-9]+(\.[0-9]+)?$`)
+	datePattern    = regexp.MustCompile(`^[0-9]{4}-[0-9]{1,2}-[0-9]{1,2}$|^[0-9]{1,2}[/.][0-9]{1,2}[/.]([0-9]{2}|[0-9]{4})$`)
+	moneyPattern   = regexp.MustCompile(`^[$£€¥][0-9][0-9,]*(\.[0-9]+)?$|^[0-9][0-9,]*(\.[0-9]+)?[$£€¥]$`)
+	percentPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?%$`)
+)
+
+// placeholderFor classifies the core of a token, returning "" for non-numeric text
+func placeholderFor(core string) string {
+	switch {
+	case percentPattern.MatchString(core):
+		return PlaceholderPercent
+	case moneyPattern.MatchString(core):
+		return PlaceholderMoney
+	case datePattern.MatchString(core):
+		return PlaceholderDate
+	case yearPattern.MatchString(core):
+		return PlaceholderYear
+	case numberPattern.MatchString(core):
+		return PlaceholderNumber
+	}
+	return ""
+}
+
+// maskNumbers replaces numeric tokens in place, keeping surrounding punctuation
+func maskNumbers(words []string) {
+	for i, w := range words {
+		start := strings.IndexFunc(w, isValueRune)
+		if start < 0 {
+			continue
+		}
+		end := strings.LastIndexFunc(w, isValueRune) + 1
+		if p := placeholderFor(w[start:end]); p != "" {
+			words[i] = w[:start] + p + w[end:]
+		}
+	}
+}
+
+func isValueRune(r rune) bool {
+	return unicode.IsDigit(r) || strings.ContainsRune("$£€¥%", r)
+}
+
+// synthesize swaps any placeholder inside word for a fresh value
+func (c PlaceholderConfig) synthesize(word string, rng *rand.Rand) string {
+	start := strings.IndexByte(word, '<')
+	if start < 0 {
+		return word
+	}
+	end := strings.IndexByte(word[start:], '>')
+	if end < 0 {
+		return word
+	}
+	end += start + 1
+
+	var value string
+	switch word[start:end] {
+	case PlaceholderNumber:
+		lo, hi := orDefault(c.NumberMin, 1), orDefault(c.NumberMax, 1000)
+		value = fmt.Sprint(lo + rng.Intn(max(hi-lo, 0)+1))
+	case PlaceholderYear:
+		lo, hi := orDefault(c.YearMin, 1900), orDefault(c.YearMax, 2030)
+		value = fmt.Sprint(lo + rng.Intn(max(hi-lo, 0)+1))
+	case PlaceholderDate:
+		lo, hi := orDefault(c.YearMin, 1900), orDefault(c.YearMax, 2030)
+		year := lo + rng.Intn(max(hi-lo, 0)+1)
+		date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, rng.Intn(365))
+		layout := c.DateLayout
+		if layout == "" {
+			layout = "2006-01-02"
+		}
+		value = date.Format(layout)
+	case PlaceholderMoney:
+		symbol := c.CurrencySymbol
+		if symbol == "" {
+			symbol = "$"
+		}
+		limit := c.MoneyMax
+		if limit <= 0 {
+			limit = 1000
+		}
+		value = fmt.Sprintf("%s%.2f", symbol, rng.Float64()*limit)
+	case PlaceholderPercent:
+		value = fmt.Sprintf("%d%%", 1+rng.Intn(100))
+	default:
+		return word
+	}
+	return word[:start] + value + word[end:]
+}
+
+func orDefault(v, fallback int) int {
+	if v == 0 {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,54 @@
+package gophertext
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// privatizedChain returns a copy of the chain with Laplace noise added to
+// every transition count and below-threshold transitions dropped, so rare
+// verbatim phrases from the corpus are unlikely to survive into a saved
+// model. Callers must hold at least the read lock.
+func (m *MarkovModel) privatizedChain() map[string]map[string]int {
+	scale := m.config.PrivacyNoise
+	threshold := m.config.PrivacyThreshold
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	chain := make(map[string]map[string]int, len(m.chain))
+	for prefix, suffixes := range m.chain {
+		words := make([]string, 0, len(suffixes))
+		for w := range suffixes {
+			words = append(words, w)
+		}
+		sort.Strings(words)
+
+		noisy := make(map[string]int)
+		for _, w := range words {
+			n := float64(suffixes[w])
+			if scale > 0 {
+				n += laplace(scale, m.rng)
+			}
+			c := int(math.Round(n))
+			if c < threshold {
+				continue
+			}
+			noisy[w] = c
+		}
+		if len(noisy) > 0 {
+			chain[prefix] = noisy
+		}
+	}
+	return chain
+}
+
+// laplace samples zero-centred Laplace noise with the given scale
+func laplace(scale float64, rng *rand.Rand) float64 {
+	u := rng.Float64() - 0.5
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
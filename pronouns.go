@@ -0,0 +1,38 @@
+package gophertext
+
+import "strings"
+
+// pronounLookback bounds how far back the current sentence is searched for
+// its first pronoun
+const pronounLookback = 40
+
+// pronounPersons maps English subject pronouns and possessive determiners
+// to their grammatical person. Object forms are left out, since "I saw
+// him" mixes persons naturally.
+var pronounPersons = map[string]string{
+	"i": "1s", "my": "1s", "mine": "1s", "myself": "1s",
+	"we": "1p", "our": "1p", "ours": "1p", "ourselves": "1p",
+	"you": "2", "your": "2", "yours": "2", "yourself": "2", "yourselves": "2",
+	"he": "3", "his": "3", "himself": "3", "she": "3", "her": "3", "hers": "3", "herself": "3",
+	"they": "3", "their": "3", "theirs": "3", "themselves": "3",
+}
+
+// pronounPerson returns the grammatical person of a pronoun token, or ""
+func pronounPerson(token string) string {
+	return pronounPersons[strings.ToLower(coreWord(token))]
+}
+
+// sentencePerson returns the person of the first pronoun in the sentence
+// that tokens end in, or "" if it has none yet
+func (m *MarkovModel) sentencePerson(tokens []string) string {
+	person := ""
+	for i := len(tokens) - 1; i >= 0 && i >= len(tokens)-pronounLookback; i-- {
+		if m.splitter.IsTerminal(tokens[i]) {
+			break
+		}
+		if p := pronounPerson(tokens[i]); p != "" {
+			person = p
+		}
+	}
+	return person
+}
@@ -0,0 +1,30 @@
+package providers_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jasonlovesdoggo/gophertext"
+	"github.com/jasonlovesdoggo/gophertext/providers"
+)
+
+func ExampleProvider_Format() {
+	model := gophertext.NewMarkovModel(gophertext.MarkovConfig{Order: 1, Seed: 5})
+	err := model.BuildModel(`The harbor town slept under fog while fishing boats waited at the pier.
+Sailors mended nets and traders counted barrels of salted herring by lantern light.`)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := providers.New(model)
+	for _, template := range []string{providers.EmailTemplate, providers.AddressTemplate} {
+		value, err := p.Format(template)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(value)
+	}
+	// Output:
+	// salted.barrels@countedpost.net
+	// 767 Traders Street, Andfield 95567
+}
@@ -0,0 +1,210 @@
+// Package providers builds faker-style values (emails, company names,
+// product copy, addresses) from trained gophertext models. Every word comes
+// from the model's own generations, so the output follows the corpus
+// vocabulary instead of a fixed word list.
+package providers
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jasonlovesdoggo/gophertext"
+)
+
+// Default templates used by the convenience methods. Templates support:
+//
+//	{word}        lowercase corpus word
+//	{Word}        capitalized corpus word
+//	{text:N}      N words of generated text
+//	{digits:N}    N random digits
+//	{pick:a|b|c}  one of the listed alternatives
+const (
+	EmailTemplate       = "{word}.{word}@{word}{pick:mail|post|box|net}.{pick:com|net|org|io}"
+	CompanyTemplate     = "{Word} {pick:& {Word}|{Word}} {pick:Ltd|Inc|LLC|Group|& Co.|Holdings}"
+	ProductTemplate     = "{Word} {Word} {pick:Pro|Max|Mini|Plus|Classic|2000}"
+	DescriptionTemplate = "{text:25}"
+	AddressTemplate     = "{digits:3} {Word} {pick:Street|Road|Lane|Avenue|Way}, {Word}{pick:ton|ville|field|bury|ford} {digits:5}"
+)
+
+// Provider renders templates with words drawn from a trained model
+type Provider struct {
+	model *gophertext.MarkovModel
+
+	mu    sync.Mutex
+	words []string // Harvested words waiting to be used
+}
+
+// New creates a provider backed by a trained model
+func New(model *gophertext.MarkovModel) *Provider {
+	return &Provider{model: model}
+}
+
+// Email returns a fake email address
+func (p *Provider) Email() (string, error) {
+	return p.Format(EmailTemplate)
+}
+
+// Company returns a fake company name
+func (p *Provider) Company() (string, error) {
+	return p.Format(CompanyTemplate)
+}
+
+// ProductName returns a fake product name
+func (p *Provider) ProductName() (string, error) {
+	return p.Format(ProductTemplate)
+}
+
+// ProductDescription returns a short generated product blurb
+func (p *Provider) ProductDescription() (string, error) {
+	return p.Format(DescriptionTemplate)
+}
+
+// Address returns a fake street address
+func (p *Provider) Address() (string, error) {
+	return p.Format(AddressTemplate)
+}
+
+// Format expands every placeholder in template
+func (p *Provider) Format(template string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			b.WriteString(template)
+			return b.String(), nil
+		}
+		end := matchingBrace(template, start)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in %q", template)
+		}
+
+		b.WriteString(template[:start])
+		value, err := p.expand(template[start+1 : end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+		template = template[end+1:]
+	}
+}
+
+func (p *Provider) expand(placeholder string) (string, error) {
+	name, arg, _ := strings.Cut(placeholder, ":")
+	switch name {
+	case "word":
+		return p.word()
+	case "Word":
+		w, err := p.word()
+		if err != nil {
+			return "", err
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		return string(r), nil
+	case "text":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("invalid word count in {%s}", placeholder)
+		}
+		return p.model.Generate(n)
+	case "digits":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("invalid digit count in {%s}", placeholder)
+		}
+		digits := make([]byte, n)
+		for i := range digits {
+			digits[i] = byte('0' + p.rng().Intn(10))
+		}
+		if digits[0] == '0' {
+			digits[0] = byte('1' + p.rng().Intn(9))
+		}
+		return string(digits), nil
+	case "pick":
+		choices := splitChoices(arg)
+		return p.Format(choices[p.rng().Intn(len(choices))])
+	default:
+		return "", fmt.Errorf("unknown placeholder {%s}", placeholder)
+	}
+}
+
+// rng is the model's random source, so a seeded model gives reproducible
+// values
+func (p *Provider) rng() *rand.Rand {
+	return p.model.Rand()
+}
+
+// word returns the next purely alphabetic word harvested from the model
+func (p *Provider) word() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for attempts := 0; len(p.words) == 0; attempts++ {
+		if attempts == 8 {
+			return "", fmt.Errorf("model produced no usable words")
+		}
+		text, err := p.model.Generate(64)
+		if err != nil {
+			return "", err
+		}
+		for _, f := range strings.Fields(text) {
+			if len([]rune(f)) >= 3 && isAlpha(f) {
+				p.words = append(p.words, strings.ToLower(f))
+			}
+		}
+	}
+
+	w := p.words[len(p.words)-1]
+	p.words = p.words[:len(p.words)-1]
+	return w, nil
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchingBrace returns the index of the brace closing the one at start
+func matchingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitChoices splits a pick list on top-level pipes so nested placeholders survive
+func splitChoices(arg string) []string {
+	var choices []string
+	depth, last := 0, 0
+	for i := 0; i < len(arg); i++ {
+		switch arg[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '|':
+			if depth == 0 {
+				choices = append(choices, arg[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(choices, arg[last:])
+}
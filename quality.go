@@ -0,0 +1,94 @@
+package gophertext
+
+// simulationWords is the length of each trial generation in SimulateQuality
+const simulationWords = 200
+
+// QualityReport summarizes trial generations run by SimulateQuality
+type QualityReport struct {
+	Samples      int     // Trial generations run
+	FallbackRate float64 // Share of steps that jumped to a random prefix
+	AvgRunLength float64 // Mean words generated between fallbacks
+	RepeatRate   float64 // Share of (Order+1)-grams already produced earlier in the same sample
+	Coverage     float64 // Share of the vocabulary that appeared in any sample
+}
+
+// SimulateQuality runs samples trial generations and reports how often
+// they dead-end, how long they run uninterrupted, how much they loop and
+// how much of the vocabulary they reach. It is a quick health check for a
+// freshly trained model.
+func (m *MarkovModel) SimulateQuality(samples int) (QualityReport, error) {
+	report := QualityReport{Samples: samples}
+	if samples <= 0 {
+		return report, nil
+	}
+
+	order := m.settings().Order
+	n := order + 1
+	seen := make(map[string]bool)
+	steps, fallbacks, runs, runWords := 0, 0, 0, 0
+	grams, repeats := 0, 0
+	for i := 0; i < samples; i++ {
+		_, stats, err := m.generate(simulationWords, newGenerateOptions(nil))
+		if err != nil {
+			return report, err
+		}
+
+		steps += len(stats.tokens) - order
+		fallbacks += stats.Fallbacks
+		for _, r := range stats.Runs {
+			runs++
+			runWords += r
+		}
+		for _, w := range stats.tokens {
+			seen[w] = true
+		}
+
+		produced := make(map[string]bool)
+		for j := 0; j+n <= len(stats.tokens); j++ {
+			gram := joinKey(stats.tokens[j : j+n])
+			grams++
+			if produced[gram] {
+				repeats++
+			}
+			produced[gram] = true
+		}
+	}
+
+	vocabulary := m.vocabulary()
+	if steps > 0 {
+		report.FallbackRate = float64(fallbacks) / float64(steps)
+	}
+	if runs > 0 {
+		report.AvgRunLength = float64(runWords) / float64(runs)
+	}
+	if grams > 0 {
+		report.RepeatRate = float64(repeats) / float64(grams)
+	}
+	if len(vocabulary) > 0 {
+		hit := 0
+		for w := range seen {
+			if vocabulary[w] {
+				hit++
+			}
+		}
+		report.Coverage = float64(hit) / float64(len(vocabulary))
+	}
+	return report, nil
+}
+
+// vocabulary collects every word appearing in the chain
+func (m *MarkovModel) vocabulary() map[string]bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	vocab := make(map[string]bool)
+	for prefix, suffixes := range m.chain {
+		for _, w := range splitKey(prefix) {
+			vocab[w] = true
+		}
+		for w := range suffixes {
+			vocab[w] = true
+		}
+	}
+	return vocab
+}
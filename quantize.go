@@ -0,0 +1,40 @@
+package gophertext
+
+import (
+	"fmt"
+	"math"
+)
+
+// Quantize buckets every transition count into one of 2^bits-1 levels on a
+// logarithmic scale, so a chain whose counts reach the thousands is stored
+// with small integer weights. Relative frequencies are approximately kept:
+// rare transitions stay rare and common ones stay common. Models whose
+// largest count already fits in the available levels are left unchanged.
+func (m *MarkovModel) Quantize(bits int) error {
+	if bits < 1 || bits > 16 {
+		return fmt.Errorf("quantization bits must be between 1 and 16, got %d", bits)
+	}
+	levels := 1<<bits - 1
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	maxCount := 0
+	for _, suffixes := range m.chain {
+		for _, n := range suffixes {
+			maxCount = max(maxCount, n)
+		}
+	}
+	if maxCount <= levels {
+		return nil
+	}
+
+	scale := float64(levels-1) / math.Log(float64(maxCount))
+	for _, suffixes := range m.chain {
+		for w, n := range suffixes {
+			suffixes[w] = 1 + int(math.Round(math.Log(float64(n))*scale))
+		}
+	}
+	m.invalidateIndex()
+	return nil
+}
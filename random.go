@@ -0,0 +1,67 @@
+package gophertext
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// lockedSource makes a random source safe for concurrent use, so one
+// *rand.Rand can serve generations running in parallel
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// newRand returns a concurrency-safe random source seeded with seed, or
+// from the clock when seed is 0
+func newRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(&lockedSource{src: rand.NewSource(seed)})
+}
+
+// SetRand makes the model draw all its randomness from r, so the same
+// source state and calls always give the same text. The model serializes
+// its draws; r must not be used elsewhere while the model holds it.
+// Generations running in parallel share r, so only sequential calls are
+// reproducible.
+func (m *MarkovModel) SetRand(r *rand.Rand) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rng = rand.New(&lockedSource{src: r})
+	m.seeded = true
+}
+
+// Rand returns the source the model draws its randomness from, so code
+// built on a model can follow its seed. It is safe for concurrent use.
+func (m *MarkovModel) Rand() *rand.Rand {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rng
+}
+
+// sortedKeys returns the keys of counts in order, so reproducible
+// generations don't depend on map iteration order
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
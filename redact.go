@@ -0,0 +1,89 @@
+package gophertext
+
+// RedactionReport summarizes what RedactVocabulary removed
+type RedactionReport struct {
+	Words       int // Distinct vocabulary words removed
+	Prefixes    int // Prefixes dropped because they contained a redacted word
+	Transitions int // Suffix occurrences removed
+	Rewired     int // Transitions added to bridge over redacted words
+}
+
+// RedactVocabulary removes every word matching predicate from the model.
+// Prefixes containing a redacted word are dropped. A transition P -> w into
+// a redacted word is rewired to skip it: P gains the continuations t of
+// (P, w) for which the resulting prefix still exists, so generation can
+// flow past the gap instead of dead-ending.
+func (m *MarkovModel) RedactVocabulary(predicate func(string) bool) RedactionReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	redacted := make(map[string]bool)
+	check := func(w string) bool {
+		r, ok := redacted[w]
+		if !ok {
+			r = predicate(w)
+			redacted[w] = r
+		}
+		return r
+	}
+
+	var report RedactionReport
+	dirty := make(map[string]bool)
+	for prefix, suffixes := range m.chain {
+		for _, w := range splitKey(prefix) {
+			if check(w) {
+				dirty[prefix] = true
+			}
+		}
+		for s := range suffixes {
+			check(s)
+		}
+	}
+	for _, r := range redacted {
+		if r {
+			report.Words++
+		}
+	}
+	if report.Words == 0 {
+		return report
+	}
+
+	next := make(map[string]map[string]int, len(m.chain))
+	for prefix, suffixes := range m.chain {
+		if dirty[prefix] {
+			report.Prefixes++
+			report.Transitions += suffixTotal(suffixes)
+			continue
+		}
+
+		words := splitKey(prefix)
+		kept := make(map[string]int, len(suffixes))
+		for s, n := range suffixes {
+			if !redacted[s] {
+				kept[s] += n
+				continue
+			}
+			report.Transitions += n
+
+			// Bridge P -> w -> t as P -> t where (P[1:], t) is still a prefix
+			through := joinKey(append(append([]string{}, words[1:]...), s))
+			for t := range m.chain[through] {
+				if redacted[t] {
+					continue
+				}
+				successor := joinKey(append(append([]string{}, words[1:]...), t))
+				if _, ok := m.chain[successor]; ok && !dirty[successor] {
+					kept[t] += n
+					report.Rewired += n
+				}
+			}
+		}
+		if len(kept) > 0 {
+			next[prefix] = kept
+		}
+	}
+
+	m.chain = next
+	m.invalidateIndex()
+	return report
+}
@@ -0,0 +1,85 @@
+package gophertext
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Remix produces a variation of text. Every sentence is replaced with
+// probability intensity by a generated sentence of similar length, the rest
+// are kept verbatim, and paragraph breaks are preserved. Intensity 0 returns
+// the text unchanged and 1 replaces every sentence.
+func (m *MarkovModel) Remix(text string, intensity float64) (string, error) {
+	if intensity < 0 || intensity > 1 {
+		return "", fmt.Errorf("remix intensity %v outside [0, 1]", intensity)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.chain) == 0 {
+		return "", fmt.Errorf("model not trained")
+	}
+
+	var paragraphs []string
+	for _, block := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		sentences := m.splitter.Split(block)
+		for i, s := range sentences {
+			if m.rng.Float64() < intensity {
+				sentences[i] = m.remixSentence(len(strings.Fields(s)))
+			}
+		}
+		paragraphs = append(paragraphs, strings.Join(sentences, " "))
+	}
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+// remixSentence generates one sentence of about n words. It walks up to 2n
+// words from a sentence start and cuts at the sentence end closest to n,
+// ending the sentence itself when the walk found none. Callers hold m.mu.
+func (m *MarkovModel) remixSentence(n int) string {
+	prefix := m.startPrefix()
+	if starts := m.prefixIndex().starts; len(starts) > 0 {
+		prefix = starts[m.rng.Intn(len(starts))]
+	}
+	tokens := splitKey(prefix)
+	o := newGenerateOptions(nil)
+
+	end := -1
+	for {
+		if m.splitter.IsTerminal(tokens[len(tokens)-1]) && (end < 0 || abs(len(tokens)-n) < abs(end-n)) {
+			end = len(tokens)
+		}
+		// Later sentence ends only move further from n
+		if end >= n || len(tokens) >= 2*n {
+			break
+		}
+		key := joinKey(tokens[max(0, len(tokens)-m.config.Order):])
+		next, ok := m.sample(key, m.chain[key], o)
+		if !ok {
+			break
+		}
+		tokens = append(tokens, next)
+	}
+
+	if end < 0 {
+		if len(tokens) > n {
+			tokens = tokens[:n]
+		}
+		last := strings.TrimRight(tokens[len(tokens)-1], ",;:")
+		tokens[len(tokens)-1] = last + "."
+	} else {
+		tokens = tokens[:end]
+	}
+	sentence := strings.Join(strings.Fields(m.formatTokens(tokens, o)), " ")
+	return capitalizeFirst(sentence)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
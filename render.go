@@ -0,0 +1,122 @@
+package gophertext
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escapes used by Render
+const (
+	ansiHeading = "\x1b[1;36m"
+	ansiReset   = "\x1b[0m"
+)
+
+// RenderOptions controls Render
+type RenderOptions struct {
+	Width                 int  // Column to wrap at (default 80)
+	Color                 bool // Highlight headings with ANSI colors
+	ParagraphSpacing      int  // Blank lines between blocks (default 1)
+	SentencesPerParagraph int  // Split paragraphs with no breaks of their own into this many sentences (0 = leave whole)
+}
+
+// Render pretty-prints a generated document for the terminal. Blocks are
+// separated by blank lines; a block starting with "#" is a heading and the
+// rest are paragraphs, wrapped to the configured width.
+func Render(w io.Writer, doc string, opts RenderOptions) error {
+	if opts.Width <= 0 {
+		opts.Width = 80
+	}
+	if opts.ParagraphSpacing <= 0 {
+		opts.ParagraphSpacing = 1
+	}
+
+	var blocks []string
+	for _, block := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n\n") {
+		if block = strings.TrimSpace(block); block == "" {
+			continue
+		}
+		if strings.HasPrefix(block, "#") {
+			blocks = append(blocks, block)
+			continue
+		}
+		blocks = append(blocks, splitSentences(strings.Fields(block), opts.SentencesPerParagraph)...)
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, block := range blocks {
+		if i > 0 {
+			bw.WriteString(strings.Repeat("\n", opts.ParagraphSpacing))
+		}
+		if strings.HasPrefix(block, "#") {
+			renderHeading(bw, strings.TrimSpace(strings.TrimLeft(block, "#")), opts)
+			continue
+		}
+		for _, line := range wrapWords(strings.Fields(block), opts.Width) {
+			bw.WriteString(line)
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// renderHeading writes a heading in color, or underlined without color
+func renderHeading(bw *bufio.Writer, heading string, opts RenderOptions) {
+	for _, line := range wrapWords(strings.Fields(heading), opts.Width) {
+		if opts.Color {
+			bw.WriteString(ansiHeading + line + ansiReset + "\n")
+			continue
+		}
+		bw.WriteString(line + "\n")
+		bw.WriteString(strings.Repeat("=", utf8.RuneCountInString(line)) + "\n")
+	}
+}
+
+// splitSentences groups words into paragraphs of n sentences each
+func splitSentences(words []string, n int) []string {
+	if n <= 0 {
+		return []string{strings.Join(words, " ")}
+	}
+	var paragraphs []string
+	start, sentences := 0, 0
+	for i, w := range words {
+		if !endsSentence(w) {
+			continue
+		}
+		if sentences++; sentences == n {
+			paragraphs = append(paragraphs, strings.Join(words[start:i+1], " "))
+			start, sentences = i+1, 0
+		}
+	}
+	if start < len(words) {
+		paragraphs = append(paragraphs, strings.Join(words[start:], " "))
+	}
+	return paragraphs
+}
+
+// wrapWords fills lines of at most width characters. A word longer than
+// width gets a line of its own.
+func wrapWords(words []string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	length := 0
+	for _, w := range words {
+		n := utf8.RuneCountInString(w)
+		if length > 0 && length+1+n > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			length = 0
+		}
+		if length > 0 {
+			line.WriteByte(' ')
+			length++
+		}
+		line.WriteString(w)
+		length += n
+	}
+	if length > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
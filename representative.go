@@ -0,0 +1,67 @@
+package gophertext
+
+import (
+	"math"
+	"sort"
+)
+
+// SampleRepresentative picks the sentences of text the model finds most
+// typical of its training corpus and returns up to n of them in document
+// order. Sentences are ranked by the mean bits per word the model needs to
+// predict them, using Kneser-Ney estimates whatever the model's Smoothing,
+// so words unseen at full order still back off to shorter contexts.
+func (m *MarkovModel) SampleRepresentative(text string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	type scored struct {
+		index int
+		bits  float64
+	}
+
+	sentences := m.SplitSentences(text)
+	candidates := make([]scored, 0, len(sentences))
+	for i, s := range sentences {
+		if bits, ok := m.typicality(m.tokenize(s)); ok {
+			candidates = append(candidates, scored{i, bits})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].bits < candidates[j].bits
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].index < candidates[j].index
+	})
+
+	picked := make([]string, len(candidates))
+	for i, c := range candidates {
+		picked[i] = sentences[c.index]
+	}
+	return picked
+}
+
+// typicality returns the mean Kneser-Ney bits per word of words, each
+// predicted from up to Order preceding words
+func (m *MarkovModel) typicality(words []string) (float64, bool) {
+	if len(words) == 0 {
+		return 0, false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.chain) == 0 {
+		return 0, false
+	}
+
+	t := m.smoothingTables()
+	vocab := float64(len(t.cont) + 1)
+	bits := 0.0
+	for i, w := range words {
+		context := words[max(0, i-m.config.Order):i]
+		bits -= math.Log2(m.kneserNey(t, context, w, 0.75, vocab))
+	}
+	return bits / float64(len(words)), true
+}
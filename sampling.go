@@ -0,0 +1,328 @@
+package gophertext
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Candidate is a possible next word and its sampling weight
+type Candidate struct {
+	Word   string
+	Weight float64 // Transition count, after negative training and anchors
+}
+
+// Sampler chooses the next word among the candidates following a prefix.
+// Candidates are never empty and all weights are positive; Sample returns
+// the index of the chosen candidate.
+type Sampler interface {
+	Sample(candidates []Candidate) int
+}
+
+// RandSampler is a Sampler that can draw from a given random source. The
+// model passes its own source, so seeded models sample reproducibly. All
+// the built-in samplers implement it.
+type RandSampler interface {
+	Sampler
+	SampleRand(candidates []Candidate, rng *rand.Rand) int
+}
+
+// defaultRand serves samplers called without a source
+var defaultRand = newRand(0)
+
+// Weighted picks candidates in proportion to their weight. This matches the
+// corpus statistics and is the default.
+type Weighted struct{}
+
+func (s Weighted) Sample(candidates []Candidate) int {
+	return s.SampleRand(candidates, defaultRand)
+}
+
+func (Weighted) SampleRand(candidates []Candidate, rng *rand.Rand) int {
+	total := 0.0
+	for _, c := range candidates {
+		total += c.Weight
+	}
+	r := rng.Float64() * total
+	for i, c := range candidates {
+		if r < c.Weight {
+			return i
+		}
+		r -= c.Weight
+	}
+	return len(candidates) - 1
+}
+
+// Uniform picks every distinct candidate with equal probability, ignoring
+// weights
+type Uniform struct{}
+
+func (s Uniform) Sample(candidates []Candidate) int {
+	return s.SampleRand(candidates, defaultRand)
+}
+
+func (Uniform) SampleRand(candidates []Candidate, rng *rand.Rand) int {
+	return rng.Intn(len(candidates))
+}
+
+// Greedy always picks the heaviest candidate, breaking ties at random
+type Greedy struct{}
+
+func (s Greedy) Sample(candidates []Candidate) int {
+	return s.SampleRand(candidates, defaultRand)
+}
+
+func (Greedy) SampleRand(candidates []Candidate, rng *rand.Rand) int {
+	best, ties := 0, 1
+	for i := 1; i < len(candidates); i++ {
+		switch w := candidates[i].Weight; {
+		case w > candidates[best].Weight:
+			best, ties = i, 1
+		case w == candidates[best].Weight:
+			ties++
+			if rng.Intn(ties) == 0 {
+				best = i
+			}
+		}
+	}
+	return best
+}
+
+// TopK samples by weight among the K heaviest candidates
+type TopK struct {
+	K int
+}
+
+func (s TopK) Sample(candidates []Candidate) int {
+	return s.SampleRand(candidates, defaultRand)
+}
+
+func (s TopK) SampleRand(candidates []Candidate, rng *rand.Rand) int {
+	order := byWeight(candidates)
+	if s.K > 0 && s.K < len(order) {
+		order = order[:s.K]
+	}
+	return order[sampleIndices(candidates, order, rng)]
+}
+
+// Nucleus samples by weight among the smallest set of heaviest candidates
+// whose combined probability reaches P
+type Nucleus struct {
+	P float64
+}
+
+func (s Nucleus) Sample(candidates []Candidate) int {
+	return s.SampleRand(candidates, defaultRand)
+}
+
+func (s Nucleus) SampleRand(candidates []Candidate, rng *rand.Rand) int {
+	order := nucleus(candidates, byWeight(candidates), s.P)
+	return order[sampleIndices(candidates, order, rng)]
+}
+
+// nucleus trims order, heaviest first, to the fewest candidates whose
+// combined weight reaches share p of all of order
+func nucleus(candidates []Candidate, order []int, p float64) []int {
+	total := 0.0
+	for _, i := range order {
+		total += candidates[i].Weight
+	}
+	kept, mass := 0, 0.0
+	for kept < len(order) && (kept == 0 || mass < p*total) {
+		mass += candidates[order[kept]].Weight
+		kept++
+	}
+	return order[:kept]
+}
+
+// byWeight returns candidate indices, heaviest first
+func byWeight(candidates []Candidate) []int {
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return candidates[order[a]].Weight > candidates[order[b]].Weight
+	})
+	return order
+}
+
+// sampleIndices picks a position in indices by candidate weight
+func sampleIndices(candidates []Candidate, indices []int, rng *rand.Rand) int {
+	subset := make([]Candidate, len(indices))
+	for i, idx := range indices {
+		subset[i] = candidates[idx]
+	}
+	return Weighted{}.SampleRand(subset, rng)
+}
+
+// WithSampler selects how each generation step picks among candidates
+func WithSampler(s Sampler) GenerateOption {
+	return func(o *generateOptions) {
+		o.sampler = s
+	}
+}
+
+// WithTemperature reshapes every step's distribution before sampling: each
+// candidate's weight is raised to the power 1/t. Below 1 sharpens it toward
+// the most common continuations, above 1 flattens it toward rare ones.
+// Values of 0 or less are ignored.
+func WithTemperature(t float64) GenerateOption {
+	return func(o *generateOptions) {
+		if t > 0 {
+			o.temperature = t
+		}
+	}
+}
+
+// WithTopK keeps only the k heaviest candidates at every step, after
+// temperature. Values below 1 are ignored.
+func WithTopK(k int) GenerateOption {
+	return func(o *generateOptions) {
+		if k > 0 {
+			o.topK = k
+		}
+	}
+}
+
+// WithTopP keeps only the smallest set of heaviest candidates whose
+// combined probability reaches p at every step, after temperature and
+// WithTopK. Values outside (0, 1) are ignored.
+func WithTopP(p float64) GenerateOption {
+	return func(o *generateOptions) {
+		if p > 0 && p < 1 {
+			o.topP = p
+		}
+	}
+}
+
+// shapes reports whether o reshapes candidates before sampling
+func (o *generateOptions) shapes() bool {
+	return o.temperature > 0 || o.topK > 0 || o.topP > 0
+}
+
+// shape applies temperature, top-k and top-p filtering to candidates, in
+// that order. The heaviest candidate always survives.
+func (o *generateOptions) shape(candidates []Candidate) []Candidate {
+	if o.temperature > 0 && o.temperature != 1 {
+		heaviest := 0.0
+		for _, c := range candidates {
+			heaviest = max(heaviest, c.Weight)
+		}
+		// Scale by the heaviest weight first so powers can't overflow
+		tempered := make([]Candidate, 0, len(candidates))
+		for _, c := range candidates {
+			if w := math.Pow(c.Weight/heaviest, 1/o.temperature); w > 0 {
+				tempered = append(tempered, Candidate{Word: c.Word, Weight: w})
+			}
+		}
+		candidates = tempered
+	}
+	if o.topK == 0 && o.topP == 0 {
+		return candidates
+	}
+
+	order := byWeight(candidates)
+	if o.topK > 0 && o.topK < len(order) {
+		order = order[:o.topK]
+	}
+	if o.topP > 0 {
+		order = nucleus(candidates, order, o.topP)
+	}
+	shaped := make([]Candidate, len(order))
+	for i, idx := range order {
+		shaped[i] = candidates[idx]
+	}
+	return shaped
+}
+
+// sample picks the next word after prefix, honouring negative training,
+// anchor words and the generation's sampler. It reports false when nothing
+// can follow the prefix.
+func (m *MarkovModel) sample(prefix string, possible map[string]int, o *generateOptions) (string, bool) {
+	if len(possible) == 0 {
+		return "", false
+	}
+	negative := m.negative[prefix]
+	if len(negative) == 0 && len(o.anchors) == 0 && len(o.boosts) == 0 && o.diversity == nil && o.sampler == nil && m.config.MaxVocabulary == 0 &&
+		o.person == "" && o.starters == nil && o.enders == nil && o.ending == 0 && !o.shapes() {
+		return m.pickSuffix(possible), true
+	}
+
+	candidates := make([]Candidate, 0, len(possible))
+	for w, n := range possible {
+		if w == UnknownToken {
+			continue
+		}
+		weight := float64(n)
+		if neg := negative[w]; neg > 0 {
+			if m.config.NegativeWeight <= 0 {
+				continue
+			}
+			weight -= m.config.NegativeWeight * float64(neg)
+		}
+		if o.anchors[w] {
+			weight *= 1 + o.anchorStrength
+		}
+		if factor, ok := o.boosts[w]; ok {
+			weight *= factor
+		}
+		if o.diversity != nil {
+			weight = o.diversity.weight(prefix, w, weight)
+		}
+		if o.person != "" {
+			if p := pronounPerson(w); p != "" && p != o.person {
+				weight /= 1 + m.config.PronounConsistency
+			}
+		}
+		if o.ending > 0 {
+			weight *= m.endingWeight(prefix, w, o)
+		}
+		if weight > 0 {
+			candidates = append(candidates, Candidate{Word: w, Weight: weight})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	if m.seeded {
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].Word < candidates[j].Word
+		})
+	}
+	candidates = m.dropBoundaryWords(prefix, candidates, o)
+	if o.shapes() {
+		candidates = o.shape(candidates)
+	}
+
+	sampler := o.sampler
+	if sampler == nil {
+		sampler = Weighted{}
+	}
+	if s, ok := sampler.(RandSampler); ok {
+		return candidates[s.SampleRand(candidates, m.rng)].Word, true
+	}
+	return candidates[sampler.Sample(candidates)].Word, true
+}
+
+// pickSuffix picks a suffix in proportion to its count
+func (m *MarkovModel) pickSuffix(counts map[string]int) string {
+	r := m.rng.Intn(suffixTotal(counts))
+	if m.seeded {
+		for _, w := range sortedKeys(counts) {
+			if r < counts[w] {
+				return w
+			}
+			r -= counts[w]
+		}
+	}
+	last := ""
+	for w, n := range counts {
+		if r < n {
+			return w
+		}
+		r -= n
+		last = w
+	}
+	return last
+}
@@ -0,0 +1,131 @@
+package gophertext
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultAbbreviations never end a sentence even though they end in a period
+var DefaultAbbreviations = []string{
+	"mr", "mrs", "ms", "dr", "prof", "rev", "st", "jr", "sr", "capt", "col",
+	"gen", "lt", "sgt", "hon", "mt", "ft", "no", "nos", "vol", "vols", "fig",
+	"p", "pp", "ch", "ed", "eds", "etc", "vs", "viz", "cf", "e.g", "i.e",
+	"a.m", "p.m", "inc", "ltd", "co", "corp", "dept", "est", "approx",
+	"jan", "feb", "mar", "apr", "jun", "jul", "aug", "sep", "sept", "oct",
+	"nov", "dec",
+}
+
+// SentenceSplitter finds sentence boundaries using stop tokens while
+// skipping abbreviations, initials and decimal numbers
+type SentenceSplitter struct {
+	stopTokens    string
+	abbreviations map[string]bool
+}
+
+// NewSentenceSplitter creates a splitter that ends sentences on any rune of
+// stopTokens, except after DefaultAbbreviations or the extra abbreviations
+func NewSentenceSplitter(stopTokens string, abbreviations ...string) *SentenceSplitter {
+	if stopTokens == "" {
+		stopTokens = ".!?"
+	}
+	s := &SentenceSplitter{
+		stopTokens:    stopTokens,
+		abbreviations: make(map[string]bool, len(DefaultAbbreviations)+len(abbreviations)),
+	}
+	s.AddAbbreviations(DefaultAbbreviations...)
+	s.AddAbbreviations(abbreviations...)
+	return s
+}
+
+// AddAbbreviations extends the abbreviation list. Entries are matched
+// case-insensitively with or without their trailing period.
+func (s *SentenceSplitter) AddAbbreviations(words ...string) {
+	for _, w := range words {
+		w = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(w), "."))
+		if w != "" {
+			s.abbreviations[w] = true
+		}
+	}
+}
+
+// clone returns a copy of s that can be changed independently
+func (s *SentenceSplitter) clone() *SentenceSplitter {
+	return &SentenceSplitter{stopTokens: s.stopTokens, abbreviations: maps.Clone(s.abbreviations)}
+}
+
+// IsTerminal reports whether token ends a sentence
+func (s *SentenceSplitter) IsTerminal(token string) bool {
+	// Closing quotes and brackets may follow the stop token
+	token = strings.TrimRightFunc(token, func(r rune) bool {
+		return unicode.Is(unicode.Pf, r) || unicode.Is(unicode.Pe, r) || r == '"' || r == '\''
+	})
+	r, size := utf8.DecodeLastRuneInString(token)
+	if size == 0 || !strings.ContainsRune(s.stopTokens, r) {
+		return false
+	}
+	if r != '.' || strings.HasSuffix(token, "..") {
+		return true
+	}
+
+	word := strings.TrimLeftFunc(token[:len(token)-size], func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if word == "" {
+		return true
+	}
+	if s.abbreviations[strings.ToLower(word)] {
+		return false
+	}
+	// Single-letter initials such as "J." in "J. R. Smith"
+	if first, n := utf8.DecodeRuneInString(word); n == len(word) && unicode.IsUpper(first) {
+		return false
+	}
+	return true
+}
+
+// Split breaks text into sentences. Whitespace inside each sentence is
+// collapsed to single spaces.
+func (s *SentenceSplitter) Split(text string) []string {
+	var sentences []string
+	var current []string
+	for _, tok := range strings.Fields(text) {
+		current = append(current, tok)
+		if s.IsTerminal(tok) {
+			sentences = append(sentences, strings.Join(current, " "))
+			current = current[:0]
+		}
+	}
+	if len(current) > 0 {
+		sentences = append(sentences, strings.Join(current, " "))
+	}
+	return sentences
+}
+
+// SplitSentences splits text using the model's stop tokens and abbreviations
+func (m *MarkovModel) SplitSentences(text string) []string {
+	return m.sentenceSplitter().Split(text)
+}
+
+// AddAbbreviations registers words that never end a sentence. They are
+// stored in the model configuration and persisted by Save.
+func (m *MarkovModel) AddAbbreviations(words ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Abbreviations = append(slices.Clip(m.config.Abbreviations), words...)
+	// Generations may still be using the old splitter, so change a copy
+	splitter := m.splitter.clone()
+	splitter.AddAbbreviations(words...)
+	m.splitter = splitter
+}
+
+// sentenceSplitter returns the model's splitter, for callers that don't
+// hold the lock. A model's splitter is replaced rather than modified, so it
+// can be used after the lock is released.
+func (m *MarkovModel) sentenceSplitter() *SentenceSplitter {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.splitter
+}
@@ -0,0 +1,170 @@
+package gophertext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+// ManifestFile is the name of the manifest SaveShards writes
+const ManifestFile = "manifest.json"
+
+// shardManifest lists the files of a sharded model
+type shardManifest struct {
+	Format int          `json:"format"` // Manifest layout version
+	Order  int          `json:"order"`
+	Shards []shardEntry `json:"shards"`
+}
+
+// shardEntry describes one shard file
+type shardEntry struct {
+	File     string `json:"file"`
+	Prefixes int    `json:"prefixes"`
+	SHA256   string `json:"sha256"`
+}
+
+// SaveShards saves the model into dir as n shard files plus a manifest, for
+// models too large to handle comfortably as one file. Prefixes are spread
+// over the shards by hash; the first shard also holds the model's metadata.
+// Each shard is a file in the Save format, checked against the SHA-256 in
+// the manifest on load.
+func (m *MarkovModel) SaveShards(dir string, n int) error {
+	if n < 1 {
+		return fmt.Errorf("invalid shard count %d", n)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create shard directory: %w", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	chains := make([]map[string]map[string]int, n)
+	for i := range chains {
+		chains[i] = make(map[string]map[string]int)
+	}
+	for prefix, suffixes := range m.savedChain() {
+		chains[shardOf(prefix, n)][prefix] = suffixes
+	}
+
+	manifest := shardManifest{Format: 1, Order: m.config.Order}
+	for i, chain := range chains {
+		meta := modelMeta{Config: m.config}
+		if i == 0 {
+			meta = modelMeta{
+				Config:   m.config,
+				Updated:  m.updated,
+				Negative: m.negative,
+				Ngrams:   m.ngrams,
+				Vocab:    m.vocab,
+				Casing:   m.casing,
+			}
+		}
+		data, err := encodeModel(meta, chain)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("shard-%05d.gt", i)
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return fmt.Errorf("failed to write shard: %w", err)
+		}
+		sum := sha256.Sum256(data)
+		manifest.Shards = append(manifest.Shards, shardEntry{
+			File:     name,
+			Prefixes: len(chain),
+			SHA256:   hex.EncodeToString(sum[:]),
+		})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// LoadShards restores a model written by SaveShards from dir within fsys,
+// reading and decoding the shards in parallel. Use os.DirFS for files on
+// disk or an embed.FS for embedded shards.
+func (m *MarkovModel) LoadShards(fsys fs.FS, dir string) error {
+	data, err := fs.ReadFile(fsys, path.Join(dir, ManifestFile))
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest shardManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if manifest.Format != 1 {
+		return fmt.Errorf("unsupported shard manifest format %d", manifest.Format)
+	}
+	if len(manifest.Shards) == 0 {
+		return fmt.Errorf("shard manifest lists no shards")
+	}
+
+	metas := make([]modelMeta, len(manifest.Shards))
+	chains := make([]map[string]map[string]int, len(manifest.Shards))
+	errs := make([]error, len(manifest.Shards))
+	var wg sync.WaitGroup
+	for i, entry := range manifest.Shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metas[i], chains[i], errs[i] = loadShard(fsys, path.Join(dir, entry.File), entry)
+		}()
+	}
+	wg.Wait()
+
+	size := 0
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("shard %s: %w", manifest.Shards[i].File, err)
+		}
+		if metas[i].Config.Order != manifest.Order {
+			return fmt.Errorf("shard %s: order %d does not match manifest order %d",
+				manifest.Shards[i].File, metas[i].Config.Order, manifest.Order)
+		}
+		size += len(chains[i])
+	}
+	chain := make(map[string]map[string]int, size)
+	for _, c := range chains {
+		for prefix, suffixes := range c {
+			chain[prefix] = suffixes
+		}
+	}
+	return m.restore(metas[0], chain)
+}
+
+// loadShard reads, verifies and decodes one shard file
+func loadShard(fsys fs.FS, name string, entry shardEntry) (modelMeta, map[string]map[string]int, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return modelMeta{}, nil, fmt.Errorf("failed to read shard: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return modelMeta{}, nil, fmt.Errorf("checksum mismatch")
+	}
+	if !bytes.HasPrefix(data, []byte(formatMagic)) {
+		return modelMeta{}, nil, fmt.Errorf("not a model file")
+	}
+	meta, chain, _, err := decodeModel(data, -1)
+	return meta, chain, err
+}
+
+// shardOf assigns a prefix to one of n shards
+func shardOf(prefix string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(prefix))
+	return int(h.Sum32() % uint32(n))
+}
@@ -0,0 +1,51 @@
+package gophertext
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+)
+
+// signedMagic prefixes signed model files
+const signedMagic = "GTSIG1"
+
+// ErrInvalidSignature is returned when a signed model fails verification
+var ErrInvalidSignature = errors.New("model signature verification failed")
+
+// SaveSigned serializes the model and signs it with an ed25519 private key.
+// The result is the magic header, the 64-byte signature and the model.
+func (m *MarkovModel) SaveSigned(key ed25519.PrivateKey) ([]byte, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid ed25519 private key length %d", len(key))
+	}
+	data, err := m.Save()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(signedMagic) + ed25519.SignatureSize + len(data))
+	buf.WriteString(signedMagic)
+	buf.Write(ed25519.Sign(key, data))
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
+// LoadVerified loads a model written by SaveSigned, refusing it unless the
+// signature verifies against pub
+func (m *MarkovModel) LoadVerified(data []byte, pub ed25519.PublicKey) error {
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid ed25519 public key length %d", len(pub))
+	}
+	header := len(signedMagic) + ed25519.SignatureSize
+	if len(data) < header || string(data[:len(signedMagic)]) != signedMagic {
+		return fmt.Errorf("%w: model is not signed", ErrInvalidSignature)
+	}
+
+	sig, payload := data[len(signedMagic):header], data[header:]
+	if !ed25519.Verify(pub, payload, sig) {
+		return ErrInvalidSignature
+	}
+	return m.Load(payload)
+}
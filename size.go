@@ -0,0 +1,104 @@
+package gophertext
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// avgTokenBytes is the typical length of a normalized English token
+const avgTokenBytes = 5
+
+// tokenHeaderBytes is the cost of one string header in a tokenized corpus
+const tokenHeaderBytes = 16
+
+// ErrOverBudget is returned by BuildModel with WithMemoryBudget when the
+// projected model doesn't fit in the budget
+var ErrOverBudget = errors.New("projected model size exceeds memory budget")
+
+// heapsLaw holds the K and beta of distinct n-grams ≈ K * transitions^beta
+// for n 1-4, fitted on English prose. Higher orders count every prefix
+// as distinct.
+var heapsLaw = [...]struct{ k, beta float64 }{
+	{14, 0.6},
+	{5.2, 0.8},
+	{2.46, 0.9},
+	{1.57, 0.95},
+}
+
+// SizeEstimate projects the size of a model before training
+type SizeEstimate struct {
+	Transitions   int   // Transitions counted from the corpus
+	Prefixes      int   // Projected distinct prefixes
+	Distinct      int   // Projected distinct transitions, each stored once with its count
+	ChainBytes    int64 // Projected heap used by the trained chain
+	TrainingBytes int64 // Projected peak heap while training, including the tokenized corpus
+}
+
+// EstimateModelSize projects how large a model trained on corpusTokens
+// tokens with cfg will be. Distinct prefixes follow Heaps' law, fitted per
+// order on English prose, so the estimate is a guide rather than a bound:
+// repetitive corpora come out smaller and highly varied ones larger.
+func EstimateModelSize(corpusTokens int, cfg MarkovConfig) SizeEstimate {
+	order := cfg.Order
+	if order < 1 {
+		order = defaultOrder
+		if cfg.Order == OrderAuto {
+			order = RecommendOrder(corpusTokens, 0)
+		}
+	}
+
+	var est SizeEstimate
+	est.Transitions = max(0, corpusTokens-order)
+	est.Prefixes = heapsEstimate(order, est.Transitions)
+	// A transition is an n-gram one word longer than its prefix
+	est.Distinct = max(est.Prefixes, heapsEstimate(order+1, est.Transitions))
+
+	keyBytes := int64(order * (avgTokenBytes + 1))
+	est.ChainBytes = int64(est.Prefixes)*(prefixOverhead+keyBytes) + int64(est.Distinct)*(suffixBytes+avgTokenBytes)
+	est.TrainingBytes = est.ChainBytes + int64(corpusTokens)*(tokenHeaderBytes+avgTokenBytes)
+	return est
+}
+
+// heapsEstimate projects the distinct n-grams among transitions n-grams of
+// length n
+func heapsEstimate(n, transitions int) int {
+	if n > len(heapsLaw) || transitions <= 0 {
+		return transitions
+	}
+	law := heapsLaw[n-1]
+	return min(transitions, int(law.k*math.Pow(float64(transitions), law.beta)))
+}
+
+// WithMemoryBudget checks EstimateModelSize against budget bytes before
+// training, counting the chain already trained. Over budget, BuildModel
+// fails with ErrOverBudget, or calls warn and trains anyway when warn is
+// not nil.
+func WithMemoryBudget(budget int64, warn func(SizeEstimate)) TrainOption {
+	return func(o *trainOptions) {
+		o.budget = budget
+		o.warnBudget = warn
+	}
+}
+
+// checkBudget applies WithMemoryBudget to a tokenized corpus
+func (m *MarkovModel) checkBudget(tokens int, o *trainOptions) error {
+	if o.budget <= 0 {
+		return nil
+	}
+	est := EstimateModelSize(tokens, m.settings())
+	m.mu.RLock()
+	existing := estimateChainBytes(m.chain)
+	m.mu.RUnlock()
+	est.ChainBytes += existing
+	est.TrainingBytes += existing
+
+	if est.TrainingBytes <= o.budget {
+		return nil
+	}
+	if o.warnBudget != nil {
+		o.warnBudget(est)
+		return nil
+	}
+	return fmt.Errorf("%w: %d bytes projected, %d allowed", ErrOverBudget, est.TrainingBytes, o.budget)
+}
@@ -0,0 +1,52 @@
+package gophertext
+
+import "hash/fnv"
+
+// countMinSketch estimates transition frequencies in fixed memory. Estimates
+// never undercount; collisions can only inflate them.
+type countMinSketch struct {
+	width  uint64
+	depth  int
+	counts []uint32
+}
+
+func newCountMinSketch(width, depth int) *countMinSketch {
+	return &countMinSketch{
+		width:  uint64(width),
+		depth:  depth,
+		counts: make([]uint32, width*depth),
+	}
+}
+
+// hashes derives the two base hashes used for double hashing across rows
+func transitionHashes(prefix, suffix string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(prefix))
+	h.Write([]byte{0})
+	h.Write([]byte(suffix))
+	h1 := h.Sum64()
+	h2 := h1>>33 | h1<<31
+	return h1, h2 | 1
+}
+
+func (s *countMinSketch) add(prefix, suffix string, n uint32) {
+	h1, h2 := transitionHashes(prefix, suffix)
+	for row := 0; row < s.depth; row++ {
+		i := uint64(row)*s.width + (h1+uint64(row)*h2)%s.width
+		if s.counts[i] <= ^uint32(0)-n {
+			s.counts[i] += n
+		}
+	}
+}
+
+func (s *countMinSketch) estimate(prefix, suffix string) uint32 {
+	h1, h2 := transitionHashes(prefix, suffix)
+	min := ^uint32(0)
+	for row := 0; row < s.depth; row++ {
+		i := uint64(row)*s.width + (h1+uint64(row)*h2)%s.width
+		if s.counts[i] < min {
+			min = s.counts[i]
+		}
+	}
+	return min
+}
@@ -0,0 +1,132 @@
+package gophertext
+
+import "math"
+
+// Smoothing selects how probability mass is given to unseen continuations
+type Smoothing int
+
+const (
+	SmoothingNone      Smoothing = iota // Raw transition frequencies
+	SmoothingAddK                       // Add SmoothingK to every count
+	SmoothingKneserNey                  // Interpolated absolute discounting down to continuation counts
+)
+
+// smoothingTables holds the lower-order statistics smoothing needs
+type smoothingTables struct {
+	lower     map[string]map[string]int // Last k words (k < Order) -> next word counts
+	cont      map[string]int            // Word -> distinct prefixes it follows
+	contTotal int                       // Distinct transitions
+}
+
+// smoothingTables returns the lower-order tables, building them if needed
+func (m *MarkovModel) smoothingTables() *smoothingTables {
+	idx := m.prefixIndex()
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	if idx.smoothing == nil {
+		idx.smoothing = m.buildSmoothingTables()
+	}
+	return idx.smoothing
+}
+
+func (m *MarkovModel) buildSmoothingTables() *smoothingTables {
+	t := &smoothingTables{
+		lower: make(map[string]map[string]int),
+		cont:  make(map[string]int),
+	}
+	for prefix, suffixes := range m.chain {
+		words := splitKey(prefix)
+		counts := suffixes
+		for w := range counts {
+			t.cont[w]++
+			t.contTotal++
+		}
+		for k := 1; k < len(words); k++ {
+			key := joinKey(words[len(words)-k:])
+			next := t.lower[key]
+			if next == nil {
+				next = make(map[string]int)
+				t.lower[key] = next
+			}
+			for w, n := range counts {
+				next[w] += n
+			}
+		}
+	}
+	return t
+}
+
+// smoothedProb is the smoothed probability of word following context,
+// where context holds at most Order words
+func (m *MarkovModel) smoothedProb(context []string, word string) float64 {
+	t := m.smoothingTables()
+	vocab := float64(len(t.cont) + 1) // One extra slot for unknown words
+
+	switch m.config.Smoothing {
+	case SmoothingAddK:
+		k := m.config.SmoothingK
+		if k <= 0 {
+			k = 1
+		}
+		counts := m.contextCounts(t, context)
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		return (float64(counts[word]) + k) / (float64(total) + k*vocab)
+	case SmoothingKneserNey:
+		d := m.config.SmoothingK
+		if d <= 0 || d >= 1 {
+			d = 0.75
+		}
+		return m.kneserNey(t, context, word, d, vocab)
+	}
+	return 0
+}
+
+func (m *MarkovModel) kneserNey(t *smoothingTables, context []string, word string, d, vocab float64) float64 {
+	if len(context) == 0 {
+		if t.contTotal == 0 {
+			return 1 / vocab
+		}
+		total := float64(t.contTotal)
+		return math.Max(float64(t.cont[word])-d, 0)/total + d*float64(len(t.cont))/total/vocab
+	}
+
+	lower := m.kneserNey(t, context[1:], word, d, vocab)
+	counts := m.contextCounts(t, context)
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if total == 0 {
+		return lower
+	}
+	return math.Max(float64(counts[word])-d, 0)/float64(total) + d*float64(len(counts))/float64(total)*lower
+}
+
+// contextCounts returns next-word counts after context, using the chain for
+// full-order contexts and the lower-order tables otherwise
+func (m *MarkovModel) contextCounts(t *smoothingTables, context []string) map[string]int {
+	key := joinKey(context)
+	if len(context) >= m.config.Order {
+		return m.chain[key]
+	}
+	return t.lower[key]
+}
+
+// backoffSample picks a next word from the longest shorter context of
+// buffer that has been seen, returning how many words of context it used.
+// Candidates go through sample like full-context ones, keyed by the whole
+// buffer, so unknown tokens, negative training, boosts and boundary rules
+// still apply.
+func (m *MarkovModel) backoffSample(buffer []string, o *generateOptions) (string, int, bool) {
+	t := m.smoothingTables()
+	prefix := joinKey(buffer)
+	for k := shorterContext(len(buffer), m.config.Order); k >= 1; k-- {
+		if w, ok := m.sample(prefix, t.lower[joinKey(buffer[len(buffer)-k:])], o); ok {
+			return w, k, true
+		}
+	}
+	return "", 0, false
+}
@@ -0,0 +1,190 @@
+package gophertext
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StreamConfig controls a StreamTrainer
+type StreamConfig struct {
+	HalfLife      time.Duration // Time for a transition's weight to halve (0 disables decay)
+	SnapshotEvery time.Duration // Interval between published snapshots (default 1 minute)
+	MinWeight     float64       // Decayed transitions below this are forgotten (default 0.5)
+	OnSnapshot    func(*MarkovModel)
+}
+
+// StreamTrainer learns continuously from a stream of lines, such as chat
+// messages arriving from a queue or webhook. Transition weights decay with
+// the configured half-life so old material fades, and a servable model is
+// published periodically.
+type StreamTrainer struct {
+	cfg    MarkovConfig
+	stream StreamConfig
+	text   *MarkovModel // Tokenization settings
+
+	mu        sync.Mutex
+	weights   map[string]map[string]float64
+	window    []string // Trailing tokens carried across lines
+	lastDecay time.Time
+	latest    *MarkovModel
+}
+
+// NewStreamTrainer creates a streaming trainer
+func NewStreamTrainer(cfg MarkovConfig, stream StreamConfig) *StreamTrainer {
+	if stream.SnapshotEvery <= 0 {
+		stream.SnapshotEvery = time.Minute
+	}
+	if stream.MinWeight <= 0 {
+		stream.MinWeight = 0.5
+	}
+	text := NewMarkovModel(cfg)
+	if text.config.Order == OrderAuto {
+		text.config.Order = defaultOrder
+	}
+	return &StreamTrainer{
+		cfg:       text.config,
+		stream:    stream,
+		text:      text,
+		weights:   make(map[string]map[string]float64),
+		lastDecay: time.Now(),
+	}
+}
+
+// AddLine trains on a single line. Lines continue each other, so a prefix
+// can span the boundary between consecutive lines.
+func (s *StreamTrainer) AddLine(line string) {
+	words := s.text.trainingTokens(line)
+	order := s.cfg.Order
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Age existing weights before adding fresh ones. Decaying in coarse steps
+	// keeps the per-line cost low while bounding how stale weights can get.
+	if now := time.Now(); s.stream.HalfLife > 0 && now.Sub(s.lastDecay) >= s.stream.HalfLife/8 {
+		s.decay(now)
+	}
+	for _, w := range words {
+		s.window = append(s.window, w)
+		if len(s.window) <= order {
+			continue
+		}
+		prefix := joinKey(s.window[:order])
+		suffixes := s.weights[prefix]
+		if suffixes == nil {
+			suffixes = make(map[string]float64)
+			s.weights[prefix] = suffixes
+		}
+		suffixes[strings.Clone(w)]++
+		s.window = append(s.window[:0], s.window[1:]...)
+	}
+}
+
+// Run consumes lines until the channel closes or ctx is cancelled,
+// publishing a snapshot every SnapshotEvery and once more on exit
+func (s *StreamTrainer) Run(ctx context.Context, lines <-chan string) error {
+	ticker := time.NewTicker(s.stream.SnapshotEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.Snapshot()
+			return ctx.Err()
+		case <-ticker.C:
+			s.Snapshot()
+		case line, ok := <-lines:
+			if !ok {
+				s.Snapshot()
+				return nil
+			}
+			s.AddLine(line)
+		}
+	}
+}
+
+// ReadFrom trains on every line read from r, snapshotting like Run
+func (s *StreamTrainer) ReadFrom(ctx context.Context, r io.Reader) error {
+	lines := make(chan string)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		errc <- scanner.Err()
+	}()
+
+	if err := s.Run(ctx, lines); err != nil {
+		return err
+	}
+	return <-errc
+}
+
+// Snapshot applies pending decay and publishes a servable model built from
+// the current weights. OnSnapshot runs after the trainer's lock is
+// released, so it may call back into the trainer.
+func (s *StreamTrainer) Snapshot() *MarkovModel {
+	s.mu.Lock()
+	s.decay(time.Now())
+	model := NewMarkovModel(s.cfg)
+	for prefix, suffixes := range s.weights {
+		counts := make(map[string]int, len(suffixes))
+		for w, weight := range suffixes {
+			counts[w] = max(1, int(math.Round(weight)))
+		}
+		model.chain[prefix] = counts
+	}
+
+	s.latest = model
+	s.mu.Unlock()
+
+	if s.stream.OnSnapshot != nil {
+		s.stream.OnSnapshot(model)
+	}
+	return model
+}
+
+// Model returns the most recently published snapshot, or nil before the first
+func (s *StreamTrainer) Model() *MarkovModel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}
+
+// decay ages every weight by the time elapsed since the last decay and
+// forgets transitions that fall below MinWeight. Callers hold s.mu.
+func (s *StreamTrainer) decay(now time.Time) {
+	if s.stream.HalfLife <= 0 {
+		return
+	}
+	elapsed := now.Sub(s.lastDecay)
+	s.lastDecay = now
+	factor := math.Pow(0.5, float64(elapsed)/float64(s.stream.HalfLife))
+
+	for prefix, suffixes := range s.weights {
+		for w, weight := range suffixes {
+			weight *= factor
+			if weight < s.stream.MinWeight {
+				delete(suffixes, w)
+			} else {
+				suffixes[w] = weight
+			}
+		}
+		if len(suffixes) == 0 {
+			delete(s.weights, prefix)
+		}
+	}
+}
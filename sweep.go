@@ -0,0 +1,85 @@
+package gophertext
+
+import (
+	"fmt"
+	"maps"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// sweepTrainRatio is the share of the corpus Sweep trains on
+const sweepTrainRatio = 0.9
+
+// EvalFunc scores a trained model on held-out text. Lower scores are better.
+type EvalFunc func(model *MarkovModel, heldOut string) float64
+
+// PerplexityEval is the default Sweep metric: held-out perplexity
+func PerplexityEval(model *MarkovModel, heldOut string) float64 {
+	return model.Evaluate(heldOut).Perplexity
+}
+
+// SweepResult is one candidate configuration scored by Sweep
+type SweepResult struct {
+	Config MarkovConfig
+	Model  *MarkovModel
+	Score  float64
+}
+
+// Sweep trains one model per candidate configuration on a training split of
+// corpus and scores each on the held-out rest with eval (PerplexityEval if
+// nil). Results are ranked best first. Candidates are trained in parallel
+// and configurations that tokenize identically share one tokenization and
+// vocabulary.
+func Sweep(corpus string, grid []MarkovConfig, eval EvalFunc) []SweepResult {
+	if eval == nil {
+		eval = PerplexityEval
+	}
+	train, heldOut := SplitCorpus(corpus, sweepTrainRatio)
+
+	results := make([]SweepResult, len(grid))
+	tokens := make(map[string][]string)
+	vocabs := make(map[string]map[string]bool)
+	for i, cfg := range grid {
+		model := NewMarkovModel(cfg)
+		key := tokenizationKey(model.config)
+		if _, ok := tokens[key]; !ok {
+			tokens[key] = model.trainingTokens(train)
+			vocabs[key] = model.vocab
+		} else {
+			// The shared tokens were capped to the first model's vocabulary
+			model.vocab = maps.Clone(vocabs[key])
+		}
+		results[i] = SweepResult{Config: cfg, Model: model}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i := range results {
+		wg.Add(1)
+		go func(r *SweepResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if cfg := r.Model.config; (cfg.RestoreCase > 0 || cfg.DetectAcronyms) && !cfg.PreserveCase {
+				r.Model.recordCasing(train)
+			}
+			r.Model.train(tokens[tokenizationKey(r.Model.config)], newTrainOptions(nil))
+			r.Score = eval(r.Model, heldOut)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score < results[j].Score
+	})
+	return results
+}
+
+// tokenizationKey identifies the configuration fields that affect tokenize
+func tokenizationKey(cfg MarkovConfig) string {
+	return fmt.Sprintf("%t|%t|%v|%s|%v|%d|%d|%d|%d", cfg.PreserveCase, cfg.EntityMode,
+		cfg.Placeholders, cfg.Language, cfg.Abbreviations, cfg.MaxVocabulary,
+		cfg.MinTokenLen, cfg.MaxTokenLen, cfg.Hyphens)
+}
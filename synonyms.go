@@ -0,0 +1,89 @@
+package gophertext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Synonyms maps a lowercase word to the words that may replace it
+type Synonyms map[string][]string
+
+// ParseSynonyms reads a synonym file in the common Solr layout, one rule
+// per line:
+//
+//	# comment
+//	quick, fast, rapid      each word may replace any other
+//	big, large => huge      big and large may become huge, not the reverse
+//
+// Words are lowercased; an entry may contain spaces.
+func ParseSynonyms(r io.Reader) (Synonyms, error) {
+	syn := make(Synonyms)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		from, to, oneWay := strings.Cut(text, "=>")
+		sources := splitSynonyms(from)
+		targets := sources
+		if oneWay {
+			targets = splitSynonyms(to)
+		}
+		if len(sources) == 0 || len(targets) == 0 || (!oneWay && len(sources) < 2) {
+			return nil, fmt.Errorf("line %d: synonym rule needs at least two words", line)
+		}
+		for _, s := range sources {
+			for _, t := range targets {
+				if s != t {
+					syn[s] = append(syn[s], t)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read synonyms: %w", err)
+	}
+	return syn, nil
+}
+
+func splitSynonyms(list string) []string {
+	var words []string
+	for _, w := range strings.Split(list, ",") {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// WithSynonyms swaps generated words for one of their synonyms with
+// probability rate, adding variety to small corpora. The chain still
+// continues from the sampled word, and prompt words are left alone.
+func WithSynonyms(syn Synonyms, rate float64) GenerateOption {
+	return func(o *generateOptions) {
+		o.synonyms = syn
+		o.synonymRate = rate
+	}
+}
+
+// substitute replaces the word inside token with a random synonym, keeping
+// surrounding punctuation and a leading capital
+func (syn Synonyms) substitute(token string, rate float64, rng *rand.Rand) string {
+	core := coreWord(token)
+	alternatives := syn[strings.ToLower(core)]
+	if len(alternatives) == 0 || rng.Float64() >= rate {
+		return token
+	}
+	pick := alternatives[rng.Intn(len(alternatives))]
+	if r, _ := utf8.DecodeRuneInString(core); unicode.IsUpper(r) {
+		pick = capitalizeFirst(pick)
+	}
+	return strings.Replace(token, core, pick, 1)
+}
@@ -0,0 +1,91 @@
+// Package textsim scores how similar two texts are by the word n-grams they
+// share. Scores range from 0 (nothing in common) to 1 (the same n-grams).
+// Words are compared case-insensitively and split on whitespace, so
+// punctuation stays attached to its word.
+package textsim
+
+import (
+	"math"
+	"strings"
+)
+
+// DefaultN is the n-gram length Similarity compares
+const DefaultN = 2
+
+// Metric scores the similarity of two texts between 0 and 1
+type Metric func(a, b string) float64
+
+// Similarity is the Jaccard similarity of the DefaultN-grams of a and b
+func Similarity(a, b string) float64 {
+	return Jaccard(DefaultN)(a, b)
+}
+
+// Jaccard compares the sets of n-grams in two texts: the number they share
+// over the number in either. Repeated n-grams count once.
+func Jaccard(n int) Metric {
+	return func(a, b string) float64 {
+		return JaccardGrams(NGrams(a, n), NGrams(b, n))
+	}
+}
+
+// Cosine compares n-gram counts as vectors, so repeated n-grams weigh more
+func Cosine(n int) Metric {
+	return func(a, b string) float64 {
+		return CosineGrams(NGrams(a, n), NGrams(b, n))
+	}
+}
+
+// NGrams counts the lowercased word n-grams of text. A text with fewer
+// than n words counts as a single n-gram, so short texts still compare.
+func NGrams(text string, n int) map[string]int {
+	if n < 1 {
+		n = 1
+	}
+	words := strings.Fields(strings.ToLower(text))
+	grams := make(map[string]int)
+	if len(words) < n {
+		if len(words) > 0 {
+			grams[strings.Join(words, " ")]++
+		}
+		return grams
+	}
+	for i := 0; i+n <= len(words); i++ {
+		grams[strings.Join(words[i:i+n], " ")]++
+	}
+	return grams
+}
+
+// JaccardGrams is the Jaccard similarity of two n-gram sets from NGrams.
+// Two empty texts are identical.
+func JaccardGrams(a, b map[string]int) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for g := range a {
+		if b[g] > 0 {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// CosineGrams is the cosine similarity of two n-gram counts from NGrams.
+// Two empty texts are identical; an empty and a non-empty one share nothing.
+func CosineGrams(a, b map[string]int) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	var dot, normA, normB float64
+	for g, n := range a {
+		dot += float64(n * b[g])
+		normA += float64(n * n)
+	}
+	for _, n := range b {
+		normB += float64(n * n)
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,20 @@
+package gophertext
+
+import "unicode/utf8"
+
+// filterTokenLengths drops tokens shorter than MinTokenLen or longer than
+// MaxTokenLen runes, reusing the slice
+func filterTokenLengths(words []string, cfg MarkovConfig) []string {
+	if cfg.MinTokenLen <= 0 && cfg.MaxTokenLen <= 0 {
+		return words
+	}
+	kept := words[:0]
+	for _, w := range words {
+		n := utf8.RuneCountInString(w)
+		if n < cfg.MinTokenLen || (cfg.MaxTokenLen > 0 && n > cfg.MaxTokenLen) {
+			continue
+		}
+		kept = append(kept, w)
+	}
+	return kept
+}
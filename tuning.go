@@ -0,0 +1,32 @@
+package gophertext
+
+import "runtime"
+
+const (
+	// smallCorpus is the size below which training runs in a single chunk
+	// on the calling goroutine; splitting costs more than it saves
+	smallCorpus = 16384
+
+	minChunkSize = 4096
+	maxChunkSize = 1 << 20
+)
+
+// trainingPlan picks the chunk size and worker count for training on n
+// words. Each worker counts a chunk in a private map before merging it, so
+// larger chunks mean fewer lock acquisitions while smaller ones balance
+// load better; auto-sizing aims for about four chunks per worker.
+func trainingPlan(cfg MarkovConfig, n int) (chunkSize, workers int) {
+	workers = cfg.TrainingWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	chunkSize = cfg.ChunkSize
+	if chunkSize <= 0 {
+		if n < smallCorpus {
+			return max(n, 1), 1
+		}
+		chunkSize = min(max(n/(workers*4), minChunkSize), maxChunkSize)
+	}
+	return chunkSize, workers
+}
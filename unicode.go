@@ -0,0 +1,36 @@
+//go:build !tinygo && !gophertext_ascii
+
+package gophertext
+
+import (
+	"unicode"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// nfc composes text into Unicode normalization form C
+func nfc(text string) string {
+	return norm.NFC.String(text)
+}
+
+// stripMarks removes diacritics by decomposing text and dropping
+// nonspacing marks
+func stripMarks(text string) string {
+	t := transform.Chain(norm.NFD, transform.RemoveFunc(func(r rune) bool {
+		return unicode.Is(unicode.Mn, r) // Mn: nonspacing marks
+	}), norm.NFC)
+	result, _, _ := transform.String(t, text)
+	return result
+}
+
+// parseLanguage canonicalizes a BCP-47 tag and returns its base language
+func parseLanguage(tag string) (canonical, base string, err error) {
+	t, err := language.Parse(tag)
+	if err != nil {
+		return "", "", err
+	}
+	b, _ := t.Base()
+	return t.String(), b.String(), nil
+}
@@ -0,0 +1,44 @@
+//go:build tinygo || gophertext_ascii
+
+package gophertext
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The tinygo and gophertext_ascii builds drop golang.org/x/text. Text is
+// not normalized, so precomposed and decomposed accents train as different
+// words; ASCII corpora are unaffected.
+
+func nfc(text string) string {
+	return text
+}
+
+// stripMarks drops nonspacing marks. Without decomposition tables only
+// already-decomposed accents are removed.
+func stripMarks(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// parseLanguage does a shallow syntax check of a BCP-47 tag and returns it
+// with a lowercased base language
+func parseLanguage(tag string) (canonical, base string, err error) {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return "", "", fmt.Errorf("language: tag is not well-formed")
+	}
+	for _, r := range parts[0] {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return "", "", fmt.Errorf("language: tag is not well-formed")
+		}
+	}
+	parts[0] = strings.ToLower(parts[0])
+	return strings.Join(parts, "-"), parts[0], nil
+}
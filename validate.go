@@ -0,0 +1,107 @@
+package gophertext
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateModel checks the model's internal invariants: a usable
+// configuration, prefixes of exactly Order words, and non-empty suffix
+// lists of non-empty tokens. Load runs it automatically so corrupt or
+// mismatched model files are rejected instead of failing mid-generation.
+func (m *MarkovModel) ValidateModel() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.validate()
+}
+
+// validate implements ValidateModel. Callers must hold at least the read lock.
+func (m *MarkovModel) validate() error {
+	return validateModel(m.config, m.chain, m.negative)
+}
+
+// validateModel checks a configuration and chain before or after they
+// become a model's, so Load can reject a model without touching the
+// current one
+func validateModel(cfg MarkovConfig, chain map[string]map[string]int, negative map[string]map[string]int) error {
+	if cfg.Order < 1 && !(cfg.Order == OrderAuto && len(chain) == 0) {
+		return fmt.Errorf("invalid model: order %d is less than 1", cfg.Order)
+	}
+	if cfg.MaxRepeat < 0 || cfg.MinSentenceLen < 0 || cfg.MaxSentenceLen < 0 || cfg.ParagraphBreak < 0 {
+		return fmt.Errorf("invalid model: negative generation limits")
+	}
+	if cfg.MinTokenLen < 0 || cfg.MaxTokenLen < 0 || (cfg.MaxTokenLen > 0 && cfg.MinTokenLen > cfg.MaxTokenLen) {
+		return fmt.Errorf("invalid model: token length limits %d-%d", cfg.MinTokenLen, cfg.MaxTokenLen)
+	}
+	if cfg.PronounConsistency < 0 {
+		return fmt.Errorf("invalid model: negative PronounConsistency %v", cfg.PronounConsistency)
+	}
+	if cfg.EndingBias < 0 {
+		return fmt.Errorf("invalid model: negative EndingBias %v", cfg.EndingBias)
+	}
+	if cfg.RestoreCase < 0 || cfg.RestoreCase > 1 {
+		return fmt.Errorf("invalid model: RestoreCase %v is outside [0, 1]", cfg.RestoreCase)
+	}
+	if cfg.Hyphens < HyphenKeep || cfg.Hyphens > HyphenBoth {
+		return fmt.Errorf("invalid model: unknown hyphen policy %d", cfg.Hyphens)
+	}
+	if cfg.MaxSentenceLen > 0 && cfg.MinSentenceLen > cfg.MaxSentenceLen {
+		return fmt.Errorf("invalid model: MinSentenceLen %d exceeds MaxSentenceLen %d",
+			cfg.MinSentenceLen, cfg.MaxSentenceLen)
+	}
+
+	for prefix, suffixes := range chain {
+		if err := validPrefix(prefix, cfg.Order); err != nil {
+			return err
+		}
+		if len(suffixes) == 0 {
+			return fmt.Errorf("invalid model: prefix %q has no suffixes", prefix)
+		}
+		for s, n := range suffixes {
+			if !validToken(s) {
+				return fmt.Errorf("invalid model: prefix %q has malformed suffix %q", prefix, s)
+			}
+			if n <= 0 {
+				return fmt.Errorf("invalid model: prefix %q has count %d for suffix %q", prefix, n, s)
+			}
+		}
+	}
+	for prefix := range negative {
+		if err := validPrefix(prefix, cfg.Order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validPrefix checks that prefix is a key of order words separated by
+// single spaces. It does not allocate, keeping Load cheap for large models.
+func validPrefix(prefix string, order int) error {
+	words := 0
+	inWord := false
+	for _, r := range prefix {
+		switch {
+		case r == ' ':
+			if !inWord {
+				return fmt.Errorf("invalid model: prefix %q is not single-space separated", prefix)
+			}
+			inWord = false
+		case !inWord:
+			words++
+			inWord = true
+		}
+	}
+	if prefix != "" && !inWord {
+		return fmt.Errorf("invalid model: prefix %q is not single-space separated", prefix)
+	}
+	if words != order {
+		return fmt.Errorf("invalid model: prefix %q has %d words, want %d", prefix, words, order)
+	}
+	return nil
+}
+
+// validToken reports whether s is a non-empty token. Tokens from a custom
+// Tokenizer may contain whitespace but never the key encoding's keySpace.
+func validToken(s string) bool {
+	return s != "" && !strings.Contains(s, keySpace)
+}
@@ -0,0 +1,88 @@
+package gophertext
+
+import (
+	"sort"
+	"strings"
+)
+
+// UnknownToken replaces words outside the vocabulary when MaxVocabulary is
+// set. It is never emitted during generation.
+const UnknownToken = "<UNK>"
+
+// fixVocabulary maps training words outside the model's vocabulary to
+// UnknownToken. The vocabulary is fixed by the first training call: its
+// MaxVocabulary most frequent words.
+func (m *MarkovModel) fixVocabulary(words []string) []string {
+	m.mu.Lock()
+	if m.vocab == nil {
+		m.vocab = topWords(words, m.config.MaxVocabulary)
+	}
+	vocab := m.vocab
+	m.mu.Unlock()
+	return capWords(words, vocab)
+}
+
+// capVocabulary maps words outside the vocabulary fixed by training to
+// UnknownToken. Before any training there is no vocabulary and words are
+// returned unchanged.
+func (m *MarkovModel) capVocabulary(words []string) []string {
+	m.mu.RLock()
+	vocab := m.vocab
+	m.mu.RUnlock()
+	if vocab == nil {
+		return words
+	}
+	return capWords(words, vocab)
+}
+
+// capWords replaces the words not in vocab with UnknownToken in place
+func capWords(words []string, vocab map[string]bool) []string {
+	for i, w := range words {
+		if !vocab[w] {
+			words[i] = UnknownToken
+		}
+	}
+	return words
+}
+
+// topWords returns the n most frequent words, breaking ties alphabetically
+func topWords(words []string, n int) map[string]bool {
+	counts := make(map[string]int)
+	for _, w := range words {
+		counts[w]++
+	}
+	ranked := make([]string, 0, len(counts))
+	for w := range counts {
+		ranked = append(ranked, w)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if counts[ranked[i]] != counts[ranked[j]] {
+			return counts[ranked[i]] > counts[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+
+	vocab := make(map[string]bool, len(ranked))
+	for _, w := range ranked {
+		vocab[w] = true
+	}
+	return vocab
+}
+
+// hasUnknown reports whether prefix contains UnknownToken
+func hasUnknown(prefix string) bool {
+	return strings.Contains(prefix, UnknownToken)
+}
+
+// startPrefix picks a random prefix to open a generation, avoiding prefixes
+// that would print UnknownToken
+func (m *MarkovModel) startPrefix() string {
+	prefix := m.randomPrefix()
+	for attempt := 0; attempt < 20 && hasUnknown(prefix); attempt++ {
+		prefix = m.randomPrefix()
+	}
+	return prefix
+}
@@ -0,0 +1,53 @@
+package gophertext
+
+import "fmt"
+
+// SetMaxTransitionWeight caps how often any one transition may be counted,
+// so a footer or disclaimer repeated thousands of times in the corpus can't
+// dominate sampling. The cap applies to the current chain at once, for
+// example right after Load, and to everything trained later. Zero removes
+// the cap without restoring counts already cut.
+func (m *MarkovModel) SetMaxTransitionWeight(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid maximum transition weight %d", n)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.MaxTransitionWeight = n
+	m.capTransitions()
+	return nil
+}
+
+// capTransitions applies MaxTransitionWeight to the whole chain. Callers
+// hold the write lock.
+func (m *MarkovModel) capTransitions() {
+	max := m.config.MaxTransitionWeight
+	if max <= 0 {
+		return
+	}
+	for _, suffixes := range m.chain {
+		for w, n := range suffixes {
+			if n > max {
+				suffixes[w] = max
+			}
+		}
+	}
+	m.invalidateIndex()
+}
+
+// addSuffixes adds suffix counts to prefix, capping each at
+// MaxTransitionWeight. Callers hold the write lock.
+func (m *MarkovModel) addSuffixes(prefix string, counts map[string]int) {
+	suffixes := m.chain[prefix]
+	if suffixes == nil {
+		suffixes = make(map[string]int, len(counts))
+		m.chain[prefix] = suffixes
+	}
+	max := m.config.MaxTransitionWeight
+	for w, n := range counts {
+		suffixes[w] += n
+		if max > 0 && suffixes[w] > max {
+			suffixes[w] = max
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package gophertext
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TrainWeighted trains on text as if it appeared weight times in the
+// corpus, without duplicating the text itself. Weights below 1 shrink a
+// document's influence; fractional counts are rounded stochastically so the
+// expected counts match the weight exactly.
+func (m *MarkovModel) TrainWeighted(text string, weight float64) error {
+	if weight <= 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return fmt.Errorf("invalid training weight %v", weight)
+	}
+
+	words := m.trainingTokens(text)
+	m.resolveOrder(words, nil)
+	part := NewMarkovModel(m.settings())
+	part.train(words, newTrainOptions(nil))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for prefix, suffixes := range part.chain {
+		scaled := make(map[string]int, len(suffixes))
+		for w, n := range suffixes {
+			if c := stochasticRound(float64(n)*weight, m.rng); c > 0 {
+				scaled[w] = c
+			}
+		}
+		if len(scaled) > 0 {
+			m.addSuffixes(prefix, scaled)
+		}
+	}
+	for h := range part.ngrams {
+		if m.ngrams == nil {
+			m.ngrams = make(map[uint64]bool)
+		}
+		m.ngrams[h] = true
+	}
+	if !m.config.Deterministic {
+		m.updated = time.Now()
+	}
+	m.invalidateIndex()
+	return nil
+}